@@ -102,13 +102,18 @@ if one is set.  Otherwise it prints the genesis from the datadir.`,
 			utils.VMTraceJsonConfigFlag,
 			utils.TransactionHistoryFlag,
 			utils.StateHistoryFlag,
+			utils.SegmentedChainFlag,
 		}, utils.DatabaseFlags),
 		Description: `
 The import command imports blocks from an RLP-encoded form. The form can be one file
 with several RLP-encoded blocks, or several files can be used.
 
 If only one file is used, import error will result in failure. If several files are used,
-processing will proceed even if an individual RLP-file import failure occurs.`,
+processing will proceed even if an individual RLP-file import failure occurs.
+
+With --segmented, the files are expected in the segmented export format. Every segment
+is verified against its checksum before import, and segments already present in the
+database are skipped, so an interrupted import can be resumed by running it again.`,
 	}
 	exportCommand = &cli.Command{
 		Action:    exportChain,
@@ -118,13 +123,18 @@ processing will proceed even if an individual RLP-file import failure occurs.`,
 		Flags: flags.Merge([]cli.Flag{
 			utils.CacheFlag,
 			utils.SyncModeFlag,
+			utils.SegmentedChainFlag,
 		}, utils.DatabaseFlags),
 		Description: `
 Requires a first argument of the file to write to.
 Optional second and third arguments control the first and
 last block to write. In this mode, the file will be appended
 if already existing. If the file ends with .gz, the output will
-be gzipped.`,
+be gzipped.
+
+With --segmented, the blocks are written in checksummed segments
+which can be verified and resumed on import. The file is always
+truncated in this mode.`,
 	}
 	importHistoryCommand = &cli.Command{
 		Action:    importHistory,
@@ -152,6 +162,32 @@ from Era archives.
 The export-history command will export blocks and their corresponding receipts
 into Era archives. Eras are typically packaged in steps of 8192 blocks.
 `,
+	}
+	corpusMaxCallsFlag = &cli.IntFlag{
+		Name:  "maxcalls",
+		Usage: "Maximum number of calls exported per contract",
+		Value: 16,
+	}
+	exportCorpusCommand = &cli.Command{
+		Action:    exportCorpus,
+		Name:      "export-corpus",
+		Usage:     "Export the bytecode and calldata of called contracts as a benchmark corpus",
+		ArgsUsage: "<filename> <first> <last>",
+		Flags: flags.Merge([]cli.Flag{
+			utils.CacheFlag,
+			corpusMaxCallsFlag,
+		}, utils.DatabaseFlags),
+		Description: `
+The export-corpus command extracts the contracts called by the transactions of
+the given block range into a JSON corpus for the execution benchmarks of the
+core/vm/runtime package:
+
+    go test ./core/vm/runtime -run - -bench Corpus -corpus <dir>
+
+Contracts are categorized by the function selectors they dispatch on, and the
+benchmarks report results per contract and per category. The state at the start
+of the blocks calling a contract for the first time must be available, so ranges
+older than the most recent 128 blocks require an archive node.`,
 	}
 	importPreimagesCommand = &cli.Command{
 		Action:    importPreimages,
@@ -313,14 +349,18 @@ func importChain(ctx *cli.Context) error {
 
 	var importErr error
 
+	importFn := utils.ImportChain
+	if ctx.Bool(utils.SegmentedChainFlag.Name) {
+		importFn = utils.ImportSegmentedChain
+	}
 	if ctx.Args().Len() == 1 {
-		if err := utils.ImportChain(chain, ctx.Args().First()); err != nil {
+		if err := importFn(chain, ctx.Args().First()); err != nil {
 			importErr = err
 			log.Error("Import error", "err", err)
 		}
 	} else {
 		for _, arg := range ctx.Args().Slice() {
-			if err := utils.ImportChain(chain, arg); err != nil {
+			if err := importFn(chain, arg); err != nil {
 				importErr = err
 				log.Error("Import error", "file", arg, "err", err)
 			}
@@ -372,7 +412,11 @@ func exportChain(ctx *cli.Context) error {
 	var err error
 	fp := ctx.Args().First()
 	if ctx.Args().Len() < 3 {
-		err = utils.ExportChain(chain, fp)
+		if ctx.Bool(utils.SegmentedChainFlag.Name) {
+			err = utils.ExportSegmentedChain(chain, fp, 0, chain.CurrentBlock().Number.Uint64())
+		} else {
+			err = utils.ExportChain(chain, fp)
+		}
 	} else {
 		// This can be improved to allow for numbers larger than 9223372036854775807
 		first, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
@@ -386,7 +430,11 @@ func exportChain(ctx *cli.Context) error {
 		if head := chain.CurrentSnapBlock(); uint64(last) > head.Number.Uint64() {
 			utils.Fatalf("Export error: block number %d larger than head block %d\n", uint64(last), head.Number.Uint64())
 		}
-		err = utils.ExportAppendChain(chain, fp, uint64(first), uint64(last))
+		if ctx.Bool(utils.SegmentedChainFlag.Name) {
+			err = utils.ExportSegmentedChain(chain, fp, uint64(first), uint64(last))
+		} else {
+			err = utils.ExportAppendChain(chain, fp, uint64(first), uint64(last))
+		}
 	}
 	if err != nil {
 		utils.Fatalf("Export error: %v\n", err)
@@ -484,6 +532,38 @@ func exportHistory(ctx *cli.Context) error {
 	return nil
 }
 
+// exportCorpus exports the contracts called in a block range as a bytecode
+// corpus for the execution benchmarks.
+func exportCorpus(ctx *cli.Context) error {
+	if ctx.Args().Len() != 3 {
+		utils.Fatalf("usage: %s", ctx.Command.ArgsUsage)
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, true)
+	defer db.Close()
+	start := time.Now()
+
+	var (
+		fp          = ctx.Args().Get(0)
+		first, ferr = strconv.ParseInt(ctx.Args().Get(1), 10, 64)
+		last, lerr  = strconv.ParseInt(ctx.Args().Get(2), 10, 64)
+	)
+	if ferr != nil || lerr != nil {
+		utils.Fatalf("Export error in parsing parameters: block number not an integer\n")
+	}
+	if first < 0 || last < first {
+		utils.Fatalf("Export error: invalid block range %d-%d\n", first, last)
+	}
+	if err := utils.ExportCorpus(chain, fp, uint64(first), uint64(last), ctx.Int(corpusMaxCallsFlag.Name)); err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
 // importPreimages imports preimage data from the specified file.
 // it is deprecated, and the export function has been removed, but
 // the import function is kept around for the time being so that
@@ -37,6 +37,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/eth/catalyst"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/internal/crash"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/internal/version"
 	"github.com/ethereum/go-ethereum/log"
@@ -192,6 +193,8 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 		cfg.Eth.OverrideVerkle = &v
 	}
 
+	configureCrashReports(stack, cfg)
+
 	backend, eth := utils.RegisterEthService(stack, &cfg.Eth)
 
 	// Create gauge with geth system and build information
@@ -282,6 +285,29 @@ func dumpConfig(ctx *cli.Context) error {
 	return nil
 }
 
+// configureCrashReports enables saving crash bundles for panics recovered in
+// long-running subsystems into the node's data directory.
+func configureCrashReports(stack *node.Node, cfg gethConfig) {
+	out, err := crashReportConfig(cfg)
+	if err != nil {
+		log.Warn("Failed to encode config for crash reports", "err", err)
+	}
+	crash.Configure(stack.ResolvePath("crashes"), out)
+}
+
+// crashReportConfig encodes the config to include in crash bundles. Bundles are
+// meant to be shared, so all credentials are removed.
+func crashReportConfig(cfg gethConfig) ([]byte, error) {
+	cfg.Eth.Genesis = nil // Way too large to include in every bundle
+
+	cfg.Ethstats.URL = "" // Contains the ethstats secret
+	cfg.Metrics.InfluxDBUsername = ""
+	cfg.Metrics.InfluxDBPassword = ""
+	cfg.Metrics.InfluxDBToken = ""
+
+	return tomlSettings.Marshal(&cfg)
+}
+
 func applyMetricConfig(ctx *cli.Context, cfg *gethConfig) {
 	if ctx.IsSet(utils.MetricsEnabledFlag.Name) {
 		cfg.Metrics.Enabled = ctx.Bool(utils.MetricsEnabledFlag.Name)
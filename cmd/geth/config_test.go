@@ -0,0 +1,77 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+)
+
+// Tests that the config saved into crash bundles doesn't contain credentials.
+func TestCrashReportConfig(t *testing.T) {
+	var cfg gethConfig
+	cfg.Eth.Genesis = core.DefaultGenesisBlock()
+	cfg.Ethstats.URL = "node:ethstats-secret@stats.example.org"
+	cfg.Metrics.InfluxDBUsername = "influx-user"
+	cfg.Metrics.InfluxDBPassword = "influx-password"
+	cfg.Metrics.InfluxDBToken = "influx-token"
+	cfg.Metrics.InfluxDBEndpoint = "http://influx.example.org:8086"
+
+	out, err := crashReportConfig(cfg)
+	if err != nil {
+		t.Fatalf("failed to encode config: %v", err)
+	}
+	for _, secret := range []string{"ethstats-secret", "influx-user", "influx-password", "influx-token"} {
+		if bytes.Contains(out, []byte(secret)) {
+			t.Errorf("crash report config contains %q:\n%s", secret, out)
+		}
+	}
+	// Non-sensitive settings must be retained, and the caller's config untouched
+	if !bytes.Contains(out, []byte("http://influx.example.org:8086")) {
+		t.Errorf("crash report config lacks the metrics endpoint:\n%s", out)
+	}
+	if cfg.Ethstats.URL == "" || cfg.Eth.Genesis == nil {
+		t.Error("caller's config was modified")
+	}
+}
+
+// Tests that unset per-method RPC limits are told apart from explicit zeroes,
+// which lift the limit for the method.
+func TestRPCMethodLimitsConfig(t *testing.T) {
+	input := `
+[Eth.RPCMethodLimits.eth_call]
+GasCap = 0
+
+[Eth.RPCMethodLimits.eth_estimateGas]
+EVMTimeout = 2000000000
+`
+	var cfg gethConfig
+	if err := tomlSettings.NewDecoder(bytes.NewReader([]byte(input))).Decode(&cfg); err != nil {
+		t.Fatalf("failed to decode config: %v", err)
+	}
+	call := cfg.Eth.RPCMethodLimits["eth_call"]
+	if call.GasCap == nil || *call.GasCap != 0 || call.EVMTimeout != nil {
+		t.Errorf("eth_call limits mismatch: %+v", call)
+	}
+	estimate := cfg.Eth.RPCMethodLimits["eth_estimateGas"]
+	if estimate.GasCap != nil || estimate.EVMTimeout == nil || *estimate.EVMTimeout != 2*time.Second {
+		t.Errorf("eth_estimateGas limits mismatch: %+v", estimate)
+	}
+}
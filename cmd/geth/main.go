@@ -59,6 +59,7 @@ var (
 		utils.PasswordFileFlag,
 		utils.BootnodesFlag,
 		utils.MinFreeDiskSpaceFlag,
+		utils.ShutdownTimeoutFlag,
 		utils.KeyStoreDirFlag,
 		utils.ExternalSignerFlag,
 		utils.NoUSBFlag, // deprecated
@@ -101,6 +102,7 @@ var (
 		utils.BloomFilterSizeFlag,
 		utils.CacheFlag,
 		utils.CacheDatabaseFlag,
+		utils.DBCompactionIntervalFlag,
 		utils.CacheTrieFlag,
 		utils.CacheTrieJournalFlag,   // deprecated
 		utils.CacheTrieRejournalFlag, // deprecated
@@ -220,6 +222,7 @@ func init() {
 		exportCommand,
 		importHistoryCommand,
 		exportHistoryCommand,
+		exportCorpusCommand,
 		importPreimagesCommand,
 		removedbCommand,
 		dumpCommand,
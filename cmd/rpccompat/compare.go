@@ -0,0 +1,194 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// rpcTest is a recorded sequence of JSON-RPC request/response pairs.
+//
+// Tests use the format of the ethereum/execution-apis test suite: requests are
+// lines prefixed with ">> ", the corresponding responses are lines prefixed with
+// "<< " and lines starting with "//" are comments.
+type rpcTest struct {
+	name      string
+	exchanges []rpcExchange
+}
+
+// rpcExchange is a single recorded request with its expected response.
+type rpcExchange struct {
+	method   string
+	request  []byte
+	response []byte
+}
+
+// parseTest parses a recorded test file.
+func parseTest(name string, blob []byte) (*rpcTest, error) {
+	test := &rpcTest{name: name}
+	for i, line := range bytes.Split(blob, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		switch {
+		case len(line) == 0 || bytes.HasPrefix(line, []byte("//")):
+			continue
+
+		case bytes.HasPrefix(line, []byte(">>")):
+			req := bytes.TrimSpace(line[2:])
+			var msg struct {
+				Method string `json:"method"`
+			}
+			if err := json.Unmarshal(req, &msg); err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid request: %v", name, i+1, err)
+			}
+			test.exchanges = append(test.exchanges, rpcExchange{method: msg.Method, request: req})
+
+		case bytes.HasPrefix(line, []byte("<<")):
+			if len(test.exchanges) == 0 || test.exchanges[len(test.exchanges)-1].response != nil {
+				return nil, fmt.Errorf("%s:%d: response without request", name, i+1)
+			}
+			test.exchanges[len(test.exchanges)-1].response = bytes.TrimSpace(line[2:])
+
+		default:
+			return nil, fmt.Errorf("%s:%d: invalid line", name, i+1)
+		}
+	}
+	for _, exchange := range test.exchanges {
+		if exchange.response == nil {
+			return nil, fmt.Errorf("%s: request %s without response", name, exchange.method)
+		}
+	}
+	return test, nil
+}
+
+// compareRules are the tolerances applied when comparing responses.
+type compareRules struct {
+	ignore       map[string]bool // Object fields excluded from the comparison
+	gasTolerance float64         // Relative tolerance of eth_estimateGas results
+}
+
+// compareResponse checks whether the actual response of a method call matches
+// the expected one. Error messages are client specific, so only the presence of
+// an error is checked for failing calls.
+func compareResponse(method string, expected, actual []byte, rules *compareRules) error {
+	var want, have map[string]interface{}
+	if err := json.Unmarshal(expected, &want); err != nil {
+		return fmt.Errorf("invalid expected response: %v", err)
+	}
+	if err := json.Unmarshal(actual, &have); err != nil {
+		return fmt.Errorf("invalid response: %v", err)
+	}
+	if _, ok := want["error"]; ok {
+		if _, ok := have["error"]; !ok {
+			return fmt.Errorf("expected error, got result %s", actual)
+		}
+		return nil
+	}
+	if err, ok := have["error"]; ok {
+		return fmt.Errorf("unexpected error: %v", err)
+	}
+	if method == "eth_estimateGas" {
+		return compareGas(want["result"], have["result"], rules.gasTolerance)
+	}
+	return compareValues("result", want["result"], have["result"], rules)
+}
+
+// compareValues recursively compares two decoded JSON values.
+func compareValues(path string, want, have interface{}, rules *compareRules) error {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		have, ok := have.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: type mismatch: have %v, want object", path, have)
+		}
+		for key, value := range want {
+			if rules.ignore[key] {
+				continue
+			}
+			if err := compareValues(path+"."+key, value, have[key], rules); err != nil {
+				return err
+			}
+		}
+		for key := range have {
+			if _, ok := want[key]; !ok && !rules.ignore[key] {
+				return fmt.Errorf("%s: unexpected field %q", path, key)
+			}
+		}
+		return nil
+
+	case []interface{}:
+		have, ok := have.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: type mismatch: have %v, want array", path, have)
+		}
+		if len(want) != len(have) {
+			return fmt.Errorf("%s: length mismatch: have %d, want %d", path, len(have), len(want))
+		}
+		for i := range want {
+			if err := compareValues(fmt.Sprintf("%s[%d]", path, i), want[i], have[i], rules); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case string:
+		// Hex strings are compared case insensitively
+		if have, ok := have.(string); ok && strings.EqualFold(want, have) {
+			return nil
+		}
+	default:
+		if reflect.DeepEqual(want, have) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: value mismatch: have %v, want %v", path, have, want)
+}
+
+// compareGas checks that a gas estimate is within the tolerated relative
+// deviation of the expected one.
+func compareGas(want, have interface{}, tolerance float64) error {
+	wantGas, err := decodeQuantity(want)
+	if err != nil {
+		return fmt.Errorf("invalid expected gas: %v", err)
+	}
+	haveGas, err := decodeQuantity(have)
+	if err != nil {
+		return fmt.Errorf("invalid gas: %v", err)
+	}
+	diff := new(big.Float).SetInt(new(big.Int).Sub(haveGas, wantGas))
+	limit := new(big.Float).Mul(new(big.Float).SetInt(wantGas), big.NewFloat(tolerance))
+	if new(big.Float).Abs(diff).Cmp(limit) > 0 {
+		return fmt.Errorf("gas estimate out of tolerance: have %v, want %v (±%.2f%%)", haveGas, wantGas, tolerance*100)
+	}
+	return nil
+}
+
+// decodeQuantity decodes a hex encoded JSON-RPC quantity.
+func decodeQuantity(value interface{}) (*big.Int, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, errors.New("quantity is not a string")
+	}
+	return hexutil.DecodeBig(str)
+}
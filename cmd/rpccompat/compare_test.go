@@ -0,0 +1,77 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+package main
+
+import "testing"
+
+func TestParseTest(t *testing.T) {
+	blob := []byte(`// retrieves the client version
+>> {"jsonrpc":"2.0","id":1,"method":"eth_chainId"}
+<< {"jsonrpc":"2.0","id":1,"result":"0xc72dd9d5e883e"}
+>> {"jsonrpc":"2.0","id":2,"method":"eth_blockNumber"}
+<< {"jsonrpc":"2.0","id":2,"result":"0x2d"}
+`)
+	test, err := parseTest("eth_chainId/get-chain-id", blob)
+	if err != nil {
+		t.Fatalf("failed to parse test: %v", err)
+	}
+	if len(test.exchanges) != 2 {
+		t.Fatalf("exchange count mismatch: have %d, want %d", len(test.exchanges), 2)
+	}
+	if test.exchanges[1].method != "eth_blockNumber" {
+		t.Fatalf("method mismatch: have %s, want %s", test.exchanges[1].method, "eth_blockNumber")
+	}
+	if _, err := parseTest("dangling", []byte(`>> {"method":"eth_chainId"}`)); err == nil {
+		t.Fatalf("request without response accepted")
+	}
+}
+
+func TestCompareResponse(t *testing.T) {
+	rules := &compareRules{ignore: map[string]bool{"timestamp": true}, gasTolerance: 0.1}
+
+	tests := []struct {
+		method string
+		want   string
+		have   string
+		fail   bool
+	}{
+		// Identical results, hex strings compared case insensitively
+		{"eth_getBalance", `{"result":"0xAB"}`, `{"result":"0xab"}`, false},
+		{"eth_getBalance", `{"result":"0xab"}`, `{"result":"0xac"}`, true},
+
+		// Ignored fields don't matter, unexpected ones do
+		{"eth_getBlockByNumber", `{"result":{"number":"0x1","timestamp":"0x1"}}`, `{"result":{"number":"0x1","timestamp":"0x2"}}`, false},
+		{"eth_getBlockByNumber", `{"result":{"number":"0x1"}}`, `{"result":{"number":"0x1","extra":"0x"}}`, true},
+
+		// Errors only need to be present, messages differ between clients
+		{"eth_call", `{"error":{"code":-32000,"message":"a"}}`, `{"error":{"code":3,"message":"b"}}`, false},
+		{"eth_call", `{"error":{"code":-32000,"message":"a"}}`, `{"result":"0x"}`, true},
+		{"eth_call", `{"result":"0x"}`, `{"error":{"code":-32000,"message":"a"}}`, true},
+
+		// Gas estimates are accepted within the relative tolerance
+		{"eth_estimateGas", `{"result":"0x5208"}`, `{"result":"0x5a3c"}`, false},
+		{"eth_estimateGas", `{"result":"0x5208"}`, `{"result":"0x6000"}`, true},
+	}
+	for i, tt := range tests {
+		err := compareResponse(tt.method, []byte(tt.want), []byte(tt.have), rules)
+		if tt.fail && err == nil {
+			t.Errorf("test %d: mismatch not detected", i)
+		}
+		if !tt.fail && err != nil {
+			t.Errorf("test %d: unexpected mismatch: %v", i, err)
+		}
+	}
+}
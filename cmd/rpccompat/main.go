@@ -0,0 +1,164 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// rpccompat replays a corpus of recorded JSON-RPC exchanges against a running
+// node and reports any responses deviating from the recorded ones.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/urfave/cli/v2"
+)
+
+var app = flags.NewApp("JSON-RPC compatibility test runner")
+
+var (
+	rpcFlag = &cli.StringFlag{
+		Name:  "rpc",
+		Usage: "HTTP endpoint of the node under test",
+		Value: "http://127.0.0.1:8545",
+	}
+	testsFlag = &cli.StringFlag{
+		Name:  "tests",
+		Usage: "directory containing the recorded .io test files",
+		Value: "tests",
+	}
+	runFlag = &cli.StringFlag{
+		Name:  "run",
+		Usage: "regular expression selecting the tests to run",
+	}
+	ignoreFlag = &cli.StringSliceFlag{
+		Name:  "ignore",
+		Usage: "response fields ignored during comparison",
+		Value: cli.NewStringSlice("timestamp"),
+	}
+	gasToleranceFlag = &cli.Float64Flag{
+		Name:  "gas-tolerance",
+		Usage: "relative tolerance accepted for eth_estimateGas results",
+		Value: 0.05,
+	}
+	timeoutFlag = &cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "timeout of a single request",
+		Value: 10 * time.Second,
+	}
+)
+
+func init() {
+	app.Flags = []cli.Flag{
+		rpcFlag,
+		testsFlag,
+		runFlag,
+		ignoreFlag,
+		gasToleranceFlag,
+		timeoutFlag,
+	}
+	app.Action = run
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run executes all the selected tests of the corpus against the node.
+func run(ctx *cli.Context) error {
+	var filter *regexp.Regexp
+	if pattern := ctx.String(runFlag.Name); pattern != "" {
+		var err error
+		if filter, err = regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid test filter: %w", err)
+		}
+	}
+	var (
+		root   = ctx.String(testsFlag.Name)
+		client = &http.Client{Timeout: ctx.Duration(timeoutFlag.Name)}
+		rules  = &compareRules{
+			ignore:       make(map[string]bool),
+			gasTolerance: ctx.Float64(gasToleranceFlag.Name),
+		}
+		passed, failed int
+	)
+	for _, field := range ctx.StringSlice(ignoreFlag.Name) {
+		rules.ignore[field] = true
+	}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".io") {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.ToSlash(strings.TrimPrefix(path, root+string(filepath.Separator))), ".io")
+		if filter != nil && !filter.MatchString(name) {
+			return nil
+		}
+		blob, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		test, err := parseTest(name, blob)
+		if err != nil {
+			return err
+		}
+		if err := runTest(client, ctx.String(rpcFlag.Name), test, rules); err != nil {
+			fmt.Printf("FAIL %s: %v\n", name, err)
+			failed++
+		} else {
+			fmt.Printf("PASS %s\n", name)
+			passed++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d passed, %d failed\n", passed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d tests failed", failed)
+	}
+	return nil
+}
+
+// runTest sends the requests of a test one by one, comparing the responses with
+// the recorded ones.
+func runTest(client *http.Client, url string, test *rpcTest, rules *compareRules) error {
+	for i, exchange := range test.exchanges {
+		res, err := client.Post(url, "application/json", bytes.NewReader(exchange.request))
+		if err != nil {
+			return fmt.Errorf("request %d: %v", i, err)
+		}
+		blob, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return fmt.Errorf("request %d: failed to read response: %v", i, err)
+		}
+		if err := compareResponse(exchange.method, exchange.response, blob, rules); err != nil {
+			return fmt.Errorf("request %d (%s): %v", i, exchange.method, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// corpusCategories maps the contract categories recognised by the corpus export
+// to the function selectors identifying them, in order of precedence. A contract
+// belongs to the first category whose selectors it all dispatches on.
+var corpusCategories = []struct {
+	name      string
+	selectors []string
+}{
+	{"amm", []string{"0x022c0d9f"}},                               // swap(uint256,uint256,address,bytes)
+	{"erc721", []string{"0x6352211e", "0x42842e0e"}},              // ownerOf(uint256), safeTransferFrom(address,address,uint256)
+	{"erc20", []string{"0xa9059cbb", "0x70a08231", "0x095ea7b3"}}, // transfer, balanceOf, approve
+}
+
+// classifyCorpusContract determines the category of a contract from the function
+// selectors pushed by its dispatcher. Contracts with no recognised category are
+// classified as proxies if they delegate calls, or as other otherwise.
+func classifyCorpusContract(code []byte) string {
+	var (
+		pushed   = make(map[string]bool)
+		delegate bool
+	)
+	for pc := 0; pc < len(code); pc++ {
+		op := vm.OpCode(code[pc])
+		switch {
+		case op == vm.PUSH4 && pc+4 < len(code):
+			pushed[hexutil.Encode(code[pc+1:pc+5])] = true
+		case op == vm.DELEGATECALL:
+			delegate = true
+		}
+		if op >= vm.PUSH1 && op <= vm.PUSH32 {
+			pc += int(op - vm.PUSH0)
+		}
+	}
+	for _, category := range corpusCategories {
+		matched := true
+		for _, selector := range category.selectors {
+			if !pushed[selector] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return category.name
+		}
+	}
+	if delegate {
+		return "proxy"
+	}
+	return "other"
+}
+
+// ExportCorpus extracts a bytecode corpus for the execution benchmarks of the
+// core/vm/runtime package from the blocks in the range [first, last], writing it
+// into the specified file. Every contract called by a transaction in the range
+// becomes an entry with its code at the start of the block of its first call,
+// along with the calldata of up to maxCalls of the transactions calling it.
+// The state of the parent of every block calling a contract not seen before
+// must be available.
+func ExportCorpus(bc *core.BlockChain, fn string, first, last uint64, maxCalls int) error {
+	log.Info("Exporting bytecode corpus", "file", fn, "first", first, "last", last)
+	if head := bc.CurrentBlock().Number.Uint64(); head < last {
+		log.Warn("Last block beyond head, setting last = head", "head", head, "last", last)
+		last = head
+	}
+	var (
+		start    = time.Now()
+		reported = time.Now()
+		entries  []*runtime.CorpusEntry
+		seen     = make(map[common.Address]*runtime.CorpusEntry) // nil for accounts without code
+	)
+	for n := max(first, 1); n <= last; n++ {
+		block := bc.GetBlockByNumber(n)
+		if block == nil {
+			return fmt.Errorf("export failed on #%d: not found", n)
+		}
+		var statedb *state.StateDB // Opened on the first contract seen in the block
+		for _, tx := range block.Transactions() {
+			to := tx.To()
+			if to == nil || len(tx.Data()) == 0 {
+				continue
+			}
+			entry, ok := seen[*to]
+			if !ok {
+				if statedb == nil {
+					parent := bc.GetHeader(block.ParentHash(), n-1)
+					if parent == nil {
+						return fmt.Errorf("export failed on #%d: parent not found", n)
+					}
+					var err error
+					if statedb, err = bc.StateAt(parent.Root); err != nil {
+						return fmt.Errorf("export failed on #%d: parent state unavailable: %w", n, err)
+					}
+				}
+				if code := statedb.GetCode(*to); len(code) > 0 {
+					entry = &runtime.CorpusEntry{
+						Name:     to.Hex(),
+						Category: classifyCorpusContract(code),
+						Code:     code,
+					}
+					entries = append(entries, entry)
+				}
+				seen[*to] = entry
+			}
+			if entry == nil || len(entry.Calls) >= maxCalls {
+				continue
+			}
+			entry.Calls = append(entry.Calls, tx.Data())
+			entry.GasLimit = max(entry.GasLimit, tx.Gas())
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Exporting bytecode corpus", "block", n, "contracts", len(entries), "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	blob, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(fn, blob, 0644); err != nil {
+		return err
+	}
+	log.Info("Exported bytecode corpus", "file", fn, "contracts", len(entries), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bytes"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestClassifyCorpusContract(t *testing.T) {
+	push4 := func(selectors ...string) []byte {
+		var code []byte
+		for _, selector := range selectors {
+			code = append(code, byte(vm.PUSH4))
+			code = append(code, common.FromHex(selector)...)
+		}
+		return code
+	}
+	tests := []struct {
+		code []byte
+		want string
+	}{
+		{push4("0xa9059cbb", "0x70a08231", "0x095ea7b3"), "erc20"},
+		{push4("0xa9059cbb", "0x70a08231"), "other"},
+		{push4("0xa9059cbb", "0x70a08231", "0x095ea7b3", "0x6352211e", "0x42842e0e"), "erc721"},
+		{push4("0x022c0d9f", "0xa9059cbb"), "amm"},
+		{[]byte{byte(vm.GAS), byte(vm.DELEGATECALL)}, "proxy"},
+		// Selectors and opcodes within push data are not matched
+		{append([]byte{byte(vm.PUSH5), byte(vm.PUSH4)}, common.FromHex("0x022c0d9f")...), "other"},
+		{[]byte{byte(vm.PUSH1), byte(vm.DELEGATECALL)}, "other"},
+		// Truncated push data at the end of the code
+		{[]byte{byte(vm.PUSH4), 0x02, 0x2c}, "other"},
+	}
+	for i, tt := range tests {
+		if have := classifyCorpusContract(tt.code); have != tt.want {
+			t.Errorf("test %d: category mismatch: have %s, want %s", i, have, tt.want)
+		}
+	}
+}
+
+func TestExportCorpus(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address  = crypto.PubkeyToAddress(key.PublicKey)
+		token    = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+		receiver = common.HexToAddress("0x000000000000000000000000000000000000bbbb")
+		// PUSH4 transfer, PUSH4 balanceOf, PUSH4 approve, STOP
+		code    = common.FromHex("0x63a9059cbb6370a0823163095ea7b300")
+		genesis = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				address: {Balance: big.NewInt(params.Ether)},
+				token:   {Code: code},
+			},
+		}
+		signer = types.LatestSigner(genesis.Config)
+	)
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 4, func(i int, g *core.BlockGen) {
+		for _, to := range []common.Address{token, receiver} {
+			tx, _ := types.SignTx(types.NewTransaction(g.TxNonce(address), to, common.Big0, 50000+uint64(i), g.BaseFee(), []byte{byte(i)}), signer, key)
+			g.AddTx(tx)
+		}
+	})
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), core.DefaultCacheConfigWithScheme(rawdb.HashScheme), genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	dir := t.TempDir()
+	if err := ExportCorpus(chain, filepath.Join(dir, "corpus.json"), 2, 4, 2); err != nil {
+		t.Fatalf("failed to export corpus: %v", err)
+	}
+	entries, err := runtime.LoadCorpus(dir)
+	if err != nil {
+		t.Fatalf("failed to load corpus: %v", err)
+	}
+	// Only the contract must be exported, with the first calls of the range
+	if len(entries) != 1 {
+		t.Fatalf("entry count mismatch: have %d, want 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Name != token.Hex() || entry.Category != "erc20" || !bytes.Equal(entry.Code, code) {
+		t.Errorf("entry mismatch: have %s/%s %x, want erc20/%s %x", entry.Category, entry.Name, entry.Code, token.Hex(), code)
+	}
+	if len(entry.Calls) != 2 || !bytes.Equal(entry.Calls[0], []byte{1}) || !bytes.Equal(entry.Calls[1], []byte{2}) {
+		t.Errorf("calls mismatch: have %x, want [01 02]", entry.Calls)
+	}
+	if entry.GasLimit != 50002 {
+		t.Errorf("gas limit mismatch: have %d, want %d", entry.GasLimit, 50002)
+	}
+}
@@ -106,6 +106,12 @@ var (
 		Value:    node.DefaultConfig.DBEngine,
 		Category: flags.EthCategory,
 	}
+	DBCompactionIntervalFlag = &cli.DurationFlag{
+		Name:     "db.compaction.interval",
+		Usage:    "Interval between idle-time compactions of the chain database (0 = disabled)",
+		Value:    ethconfig.Defaults.DatabaseCompactionInterval,
+		Category: flags.EthCategory,
+	}
 	AncientFlag = &flags.DirectoryFlag{
 		Name:     "datadir.ancient",
 		Usage:    "Root directory for ancient data (default = inside chaindata)",
@@ -116,6 +122,11 @@ var (
 		Usage:    "Minimum free disk space in MB, once reached triggers auto shut down (default = --cache.gc converted to MB, 0 = disabled)",
 		Category: flags.EthCategory,
 	}
+	ShutdownTimeoutFlag = &cli.DurationFlag{
+		Name:     "shutdown.timeout",
+		Usage:    "Deadline for each service to stop before goroutines are dumped and the service skipped (0 = wait indefinitely)",
+		Category: flags.MiscCategory,
+	}
 	KeyStoreDirFlag = &flags.DirectoryFlag{
 		Name:     "keystore",
 		Usage:    "Directory for the keystore (default = inside the datadir)",
@@ -600,6 +611,11 @@ var (
 		Usage:    "Disables db compaction after import",
 		Category: flags.LoggingCategory,
 	}
+	SegmentedChainFlag = &cli.BoolFlag{
+		Name:     "segmented",
+		Usage:    "Use the checksummed, resumable segmented format for chain export and import",
+		Category: flags.MiscCategory,
+	}
 
 	// MISC settings
 	SyncTargetFlag = &cli.StringFlag{
@@ -1425,6 +1441,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(InsecureUnlockAllowedFlag.Name) {
 		cfg.InsecureUnlockAllowed = ctx.Bool(InsecureUnlockAllowedFlag.Name)
 	}
+	if ctx.IsSet(ShutdownTimeoutFlag.Name) {
+		cfg.ShutdownTimeout = ctx.Duration(ShutdownTimeoutFlag.Name)
+	}
 	if ctx.IsSet(DBEngineFlag.Name) {
 		dbEngine := ctx.String(DBEngineFlag.Name)
 		if dbEngine != "leveldb" && dbEngine != "pebble" {
@@ -1686,6 +1705,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(AncientFlag.Name) {
 		cfg.DatabaseFreezer = ctx.String(AncientFlag.Name)
 	}
+	if ctx.IsSet(DBCompactionIntervalFlag.Name) {
+		cfg.DatabaseCompactionInterval = ctx.Duration(DBCompactionIntervalFlag.Name)
+	}
 
 	if gcmode := ctx.String(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
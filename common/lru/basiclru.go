@@ -25,6 +25,8 @@ type BasicLRU[K comparable, V any] struct {
 	list  *list[K]
 	items map[K]cacheItem[K, V]
 	cap   int
+
+	hits, misses, evictions uint64 // Usage statistics, see Stats
 }
 
 type cacheItem[K any, V any] struct {
@@ -60,6 +62,7 @@ func (c *BasicLRU[K, V]) Add(key K, value V) (evicted bool) {
 	if c.Len() >= c.cap {
 		elem = c.list.removeLast()
 		delete(c.items, elem.v)
+		c.evictions++
 		evicted = true
 	} else {
 		elem = new(listElem[K])
@@ -83,8 +86,10 @@ func (c *BasicLRU[K, V]) Contains(key K) bool {
 func (c *BasicLRU[K, V]) Get(key K) (value V, ok bool) {
 	item, ok := c.items[key]
 	if !ok {
+		c.misses++
 		return value, false
 	}
+	c.hits++
 	c.list.moveToFront(item.elem)
 	return item.value, true
 }
@@ -148,6 +153,29 @@ func (c *BasicLRU[K, V]) Keys() []K {
 	return c.list.appendTo(keys)
 }
 
+// Stats returns the usage statistics of the cache. Size and capacity are
+// measured in items.
+func (c *BasicLRU[K, V]) Stats() Stats {
+	return Stats{
+		Items:     uint64(len(c.items)),
+		Size:      uint64(len(c.items)),
+		Capacity:  uint64(c.cap),
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+}
+
+// Stats is a snapshot of the usage statistics of a cache.
+type Stats struct {
+	Items     uint64 // Number of items in the cache
+	Size      uint64 // Current size of the cache, in the unit of its capacity
+	Capacity  uint64 // Maximum size of the cache
+	Hits      uint64 // Number of lookups which found their item
+	Misses    uint64 // Number of lookups which didn't find their item
+	Evictions uint64 // Number of items dropped to make room for new ones
+}
+
 // list is a doubly-linked list holding items of type he.
 // The zero value is not valid, use newList to create lists.
 type list[T any] struct {
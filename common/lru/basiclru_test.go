@@ -184,6 +184,22 @@ func TestBasicLRUPeek(t *testing.T) {
 	}
 }
 
+func TestBasicLRUStats(t *testing.T) {
+	cache := NewBasicLRU[int, int](2)
+	cache.Add(1, 1)
+	cache.Add(2, 2)
+	cache.Add(3, 3) // evicts 1
+	cache.Get(1)
+	cache.Get(2)
+	cache.Get(3)
+	cache.Peek(2) // not counted
+
+	want := Stats{Items: 2, Size: 2, Capacity: 2, Hits: 2, Misses: 1, Evictions: 1}
+	if have := cache.Stats(); have != want {
+		t.Fatalf("stats mismatch: have %+v, want %+v", have, want)
+	}
+}
+
 func BenchmarkLRU(b *testing.B) {
 	var (
 		capacity = 1000
@@ -37,6 +37,8 @@ type SizeConstrainedCache[K comparable, V blobType] struct {
 	maxSize uint64
 	lru     BasicLRU[K, V]
 	lock    sync.Mutex
+
+	evictions uint64 // Number of items evicted to respect the size limit
 }
 
 // NewSizeConstrainedCache creates a new size-constrained LRU cache.
@@ -67,6 +69,7 @@ func (c *SizeConstrainedCache[K, V]) Add(key K, value V) (evicted bool) {
 				// list is now empty. Break
 				break
 			}
+			c.evictions++
 			targetSize -= uint64(len(v))
 		}
 		c.size = targetSize
@@ -82,3 +85,16 @@ func (c *SizeConstrainedCache[K, V]) Get(key K) (V, bool) {
 
 	return c.lru.Get(key)
 }
+
+// Stats returns the usage statistics of the cache. Size and capacity are
+// measured in bytes.
+func (c *SizeConstrainedCache[K, V]) Stats() Stats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats := c.lru.Stats()
+	stats.Size = c.size
+	stats.Capacity = c.maxSize
+	stats.Evictions = c.evictions
+	return stats
+}
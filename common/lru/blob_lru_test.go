@@ -153,3 +153,17 @@ func TestSizeConstrainedCacheEmpties(t *testing.T) {
 		}
 	}
 }
+
+func TestSizeConstrainedCacheStats(t *testing.T) {
+	lru := NewSizeConstrainedCache[testKey, []byte](10)
+	lru.Add(mkKey(1), make([]byte, 4))
+	lru.Add(mkKey(2), make([]byte, 4))
+	lru.Add(mkKey(3), make([]byte, 4)) // evicts 1
+	lru.Get(mkKey(1))
+	lru.Get(mkKey(3))
+
+	want := Stats{Items: 2, Size: 8, Capacity: 10, Hits: 1, Misses: 1, Evictions: 1}
+	if have := lru.Stats(); have != want {
+		t.Fatalf("stats mismatch: have %+v, want %+v", have, want)
+	}
+}
@@ -93,3 +93,11 @@ func (c *Cache[K, V]) Keys() []K {
 
 	return c.cache.Keys()
 }
+
+// Stats returns the usage statistics of the cache.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cache.Stats()
+}
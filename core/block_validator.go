@@ -49,17 +49,46 @@ func NewBlockValidator(config *params.ChainConfig, blockchain *BlockChain) *Bloc
 // header's transaction and uncle roots. The headers are assumed to be already
 // validated at this point.
 func (v *BlockValidator) ValidateBody(block *types.Block) error {
+	return v.validateBody(block, func() error { return v.ValidateBodyContent(block) })
+}
+
+// ValidateVerifiedBody validates the given block like ValidateBody, but reuses
+// the result of a ValidateBodyContent call made ahead of time instead of checking
+// the body content again.
+func (v *BlockValidator) ValidateVerifiedBody(block *types.Block, content error) error {
+	return v.validateBody(block, func() error { return content })
+}
+
+// validateBody runs the chain dependent body checks of the given block, with
+// the content checks done by the given callback.
+func (v *BlockValidator) validateBody(block *types.Block, content func() error) error {
 	// Check whether the block is already imported.
 	if v.bc.HasBlockAndState(block.Hash(), block.NumberU64()) {
 		return ErrKnownBlock
 	}
-
-	// Header validity is known at this point. Here we verify that uncles, transactions
-	// and withdrawals given in the block body match the header.
-	header := block.Header()
+	// Header validity is known at this point. Here we verify the uncles and that
+	// the content of the block body match the header.
 	if err := v.bc.engine.VerifyUncles(v.bc, block); err != nil {
 		return err
 	}
+	if err := content(); err != nil {
+		return err
+	}
+	// Ancestor block must be known.
+	if !v.bc.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
+		if !v.bc.HasBlock(block.ParentHash(), block.NumberU64()-1) {
+			return consensus.ErrUnknownAncestor
+		}
+		return consensus.ErrPrunedAncestor
+	}
+	return nil
+}
+
+// ValidateBodyContent verifies that the transactions, uncles, withdrawals and
+// blobs given in the block body match the header. The checks don't depend on
+// the chain, so they may run before the block's ancestors are imported.
+func (v *BlockValidator) ValidateBodyContent(block *types.Block) error {
+	header := block.Header()
 	if hash := types.CalcUncleHash(block.Uncles()); hash != header.UncleHash {
 		return fmt.Errorf("uncle root hash mismatch (header value %x, calculated %x)", header.UncleHash, hash)
 	}
@@ -106,14 +135,6 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 			return errors.New("data blobs present in block body")
 		}
 	}
-
-	// Ancestor block must be known.
-	if !v.bc.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
-		if !v.bc.HasBlock(block.ParentHash(), block.NumberU64()-1) {
-			return consensus.ErrUnknownAncestor
-		}
-		return consensus.ErrPrunedAncestor
-	}
 	return nil
 }
 
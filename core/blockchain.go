@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/common/prque"
@@ -43,6 +44,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/caches"
+	"github.com/ethereum/go-ethereum/internal/crash"
 	"github.com/ethereum/go-ethereum/internal/syncx"
 	"github.com/ethereum/go-ethereum/internal/version"
 	"github.com/ethereum/go-ethereum/log"
@@ -90,6 +93,7 @@ var (
 
 	blockPrefetchExecuteTimer   = metrics.NewRegisteredTimer("chain/prefetch/executes", nil)
 	blockPrefetchInterruptMeter = metrics.NewRegisteredMeter("chain/prefetch/interrupts", nil)
+	blockLookaheadTimer         = metrics.NewRegisteredTimer("chain/prewarm/lookahead", nil)
 
 	errInsertionInterrupted = errors.New("insertion is interrupted")
 	errChainStopped         = errors.New("blockchain is stopped")
@@ -103,6 +107,10 @@ const (
 	receiptsCacheLimit = 32
 	txLookupCacheLimit = 1024
 
+	// blockLookaheadDepth is the number of blocks beyond the executing one whose
+	// bodies are verified and state pre-warmed during chain import.
+	blockLookaheadDepth = 4
+
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
 	//
 	// Changelog:
@@ -133,7 +141,7 @@ const (
 // and state snapshot these are resident in a blockchain.
 type CacheConfig struct {
 	TrieCleanLimit      int           // Memory allowance (MB) to use for caching trie nodes in memory
-	TrieCleanNoPrefetch bool          // Whether to disable heuristic state prefetching for followup blocks
+	TrieCleanNoPrefetch bool          // Whether to disable heuristic state prefetching and pre-warming of blocks
 	TrieDirtyLimit      int           // Memory limit (MB) at which to start flushing dirty trie nodes to disk
 	TrieDirtyDisabled   bool          // Whether to disable trie write caching and GC altogether (archive node)
 	TrieTimeLimit       time.Duration // Time limit after which to flush the current in-memory trie to disk
@@ -248,6 +256,8 @@ type BlockChain struct {
 	txLookupLock  sync.RWMutex
 	txLookupCache *lru.Cache[common.Hash, txLookup]
 
+	unregisterCaches func() // Removes the caches above from the cache registry
+
 	wg            sync.WaitGroup
 	quit          chan struct{} // shutdown signal, closed in Stop.
 	stopping      atomic.Bool   // false if chain is running, true when stopped
@@ -467,9 +477,42 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	if txLookupLimit != nil {
 		bc.txIndexer = newTxIndexer(*txLookupLimit, bc)
 	}
+	bc.unregisterCaches = bc.registerCaches()
 	return bc, nil
 }
 
+// registerCaches adds the in-memory caches of the chain, the state database
+// and the trie database to the cache registry. The returned function removes
+// them again.
+func (bc *BlockChain) registerCaches() func() {
+	var unregister []func()
+	for name, stats := range map[string]caches.StatsFunc{
+		"chain/bodies":    caches.LRU(caches.UnitItems, bc.bodyCache.Stats),
+		"chain/bodiesRLP": caches.LRU(caches.UnitItems, bc.bodyRLPCache.Stats),
+		"chain/receipts":  caches.LRU(caches.UnitItems, bc.receiptsCache.Stats),
+		"chain/blocks":    caches.LRU(caches.UnitItems, bc.blockCache.Stats),
+		"chain/txlookups": caches.LRU(caches.UnitItems, bc.txLookupCache.Stats),
+		"chain/headers":   caches.LRU(caches.UnitItems, bc.hc.headerCache.Stats),
+		"chain/tds":       caches.LRU(caches.UnitItems, bc.hc.tdCache.Stats),
+		"chain/numbers":   caches.LRU(caches.UnitItems, bc.hc.numberCache.Stats),
+		"state/code":      caches.LRU(caches.UnitBytes, func() lru.Stats { return bc.statedb.CodeCacheStats() }),
+		"state/codeSize":  caches.LRU(caches.UnitItems, func() lru.Stats { return bc.statedb.CodeSizeCacheStats() }),
+		"trie/cleanNodes": func() caches.Stats {
+			if stats := bc.triedb.CleanCacheStats(); stats != nil {
+				return caches.Fastcache(stats)
+			}
+			return caches.Stats{Unit: caches.UnitBytes}
+		},
+	} {
+		unregister = append(unregister, caches.Register(name, stats))
+	}
+	return func() {
+		for _, fn := range unregister {
+			fn()
+		}
+	}
+}
+
 // empty returns an indicator whether the blockchain is empty.
 // Note, it's a special case that we connect a non-empty ancient
 // database with an empty node, so that we can plugin the ancient
@@ -1159,6 +1202,8 @@ func (bc *BlockChain) Stop() {
 	if bc.logger != nil && bc.logger.OnClose != nil {
 		bc.logger.OnClose()
 	}
+	bc.unregisterCaches()
+
 	// Close the trie database, release all the held resources as the last step.
 	if err := bc.triedb.Close(); err != nil {
 		log.Error("Failed to close trie database", "err", err)
@@ -1714,6 +1759,36 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, makeWitness
 	// Track the singleton witness from this chain insertion (if any)
 	var witness *stateless.Witness
 
+	// Verify the bodies and pre-warm the statically referenced state of the
+	// upcoming blocks while the current one executes. This is the only pre-warm
+	// path of the import. The state is read at the parent of the executing
+	// block, so it's only approximate, but most of the touched accounts and trie
+	// paths won't change in between.
+	var warm func(*types.Block, common.Hash, *atomic.Bool)
+	if !bc.cacheConfig.TrieCleanNoPrefetch {
+		threads := max(1, runtime.NumCPU()/blockLookaheadDepth)
+		warm = func(block *types.Block, root common.Hash, interrupt *atomic.Bool) {
+			start := time.Now()
+			crash.Guard("block prewarm", blockCrashDetails(block), func() {
+				bc.prefetcher.Prewarm(block, root, bc.statedb, threads, interrupt)
+			})
+			blockLookaheadTimer.Update(time.Since(start))
+		}
+	}
+	if block != nil {
+		var root common.Hash
+		if parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1); parent != nil {
+			root = parent.Root
+		}
+		// Body contents can only be checked ahead by the built-in validator
+		var verify func(*types.Block) error
+		if v, ok := bc.validator.(*BlockValidator); ok {
+			verify = v.ValidateBodyContent
+		}
+		it.lookahead = newBlockLookahead(it.chain, root, it.index, blockLookaheadDepth, verify, warm)
+		defer it.lookahead.stop()
+	}
+
 	for ; block != nil && err == nil || errors.Is(err, ErrKnownBlock); block, err = it.next() {
 		// If the chain is terminating, stop processing blocks
 		if bc.insertStopped() {
@@ -1815,7 +1890,6 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool, makeWitness
 				}(time.Now(), followup, throwaway)
 			}
 		}
-
 		// The traced section of block import.
 		res, err := bc.processBlock(block, statedb, start, setHead)
 		followupInterrupt.Store(true)
@@ -1881,6 +1955,10 @@ type blockProcessingResult struct {
 // processBlock executes and validates the given block. If there was no error
 // it writes the block and associated state to database.
 func (bc *BlockChain) processBlock(block *types.Block, statedb *state.StateDB, start time.Time, setHead bool) (_ *blockProcessingResult, blockEndErr error) {
+	// A panic during processing leaves the state in an unknown condition, so the
+	// node can't continue, but save the offending block for later analysis
+	defer crash.Handle("block import", blockCrashDetails(block))
+
 	if bc.logger != nil && bc.logger.OnBlockStart != nil {
 		td := bc.GetTd(block.ParentHash(), block.NumberU64()-1)
 		bc.logger.OnBlockStart(tracing.BlockEvent{
@@ -2537,3 +2615,19 @@ func (bc *BlockChain) SetTrieFlushInterval(interval time.Duration) {
 func (bc *BlockChain) GetTrieFlushInterval() time.Duration {
 	return time.Duration(bc.flushInterval.Load())
 }
+
+// blockCrashDetails returns the crash report details describing a block.
+func blockCrashDetails(block *types.Block) crash.Details {
+	return func() map[string]interface{} {
+		blob, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			return map[string]interface{}{"number": block.NumberU64(), "hash": block.Hash(), "error": err.Error()}
+		}
+		return map[string]interface{}{
+			"number": block.NumberU64(),
+			"hash":   block.Hash(),
+			"header": block.Header(),
+			"rlp":    hexutil.Bytes(blob),
+		}
+	}
+}
@@ -97,8 +97,9 @@ type insertIterator struct {
 	results <-chan error // Verification result sink from the consensus engine
 	errors  []error      // Header verification errors for the blocks
 
-	index     int       // Current offset of the iterator
-	validator Validator // Validator to run if verification succeeds
+	index     int             // Current offset of the iterator
+	validator Validator       // Validator to run if verification succeeds
+	lookahead *blockLookahead // Pipeline verifying bodies ahead of the iterator (optional)
 }
 
 // newInsertIterator creates a new iterator based on the given blocks, which are
@@ -123,13 +124,22 @@ func (it *insertIterator) next() (*types.Block, error) {
 	}
 	// Advance the iterator and wait for verification result if not yet done
 	it.index++
+	if it.lookahead != nil {
+		it.lookahead.advance(it.index)
+	}
 	if len(it.errors) <= it.index {
 		it.errors = append(it.errors, <-it.results)
 	}
 	if it.errors[it.index] != nil {
 		return it.chain[it.index], it.errors[it.index]
 	}
-	// Block header valid, run body validation and return
+	// Block header valid, run body validation and return. If the lookahead
+	// already checked the body content, only the chain dependent checks are run.
+	if v, ok := it.validator.(*BlockValidator); ok && it.lookahead != nil {
+		if content, ok := it.lookahead.result(it.index); ok {
+			return it.chain[it.index], v.ValidateVerifiedBody(it.chain[it.index], content)
+		}
+	}
 	return it.chain[it.index], it.validator.ValidateBody(it.chain[it.index])
 }
 
@@ -0,0 +1,167 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// blockLookahead is a bounded pipeline running ahead of chain import. While the
+// block at the head of the import executes, a single background worker checks
+// the bodies of the following blocks against their headers and warms the state
+// they statically reference, never getting more than a fixed number of blocks
+// ahead of the import. The first executing block, which had no chance to be
+// looked ahead at, is only warmed.
+//
+// The receipts of the upcoming blocks only come into existence by executing
+// them, so the pipeline can't verify those; it derives the transaction hashes
+// needed to assemble and index them instead.
+type blockLookahead struct {
+	chain types.Blocks // Chain of blocks being imported
+	root  common.Hash  // State root of the chain's parent
+	start int          // Index of the first block executed by the import
+	depth int          // Number of blocks to run ahead of the import head
+
+	verify func(block *types.Block) error                                     // Body content verifier, nil if unavailable
+	warm   func(block *types.Block, root common.Hash, interrupt *atomic.Bool) // State pre-warmer, nil if disabled
+
+	errs []error         // Body content verification results
+	done []chan struct{} // Closed when the verification result of a block is available
+
+	head      atomic.Int64  // Index of the block being imported
+	progress  chan struct{} // Notification channel for import head changes
+	interrupt atomic.Bool   // Flag to abort the state warming
+	quit      chan struct{} // Termination request channel
+	term      chan struct{} // Closed when the worker exits
+}
+
+// newBlockLookahead creates a lookahead pipeline over the given chain, whose
+// parent state is at root, and starts its background worker with the import at
+// the block at index head.
+func newBlockLookahead(chain types.Blocks, root common.Hash, head, depth int, verify func(*types.Block) error, warm func(*types.Block, common.Hash, *atomic.Bool)) *blockLookahead {
+	l := &blockLookahead{
+		chain:    chain,
+		root:     root,
+		start:    head,
+		depth:    depth,
+		verify:   verify,
+		warm:     warm,
+		errs:     make([]error, len(chain)),
+		done:     make([]chan struct{}, len(chain)),
+		progress: make(chan struct{}, 1),
+		quit:     make(chan struct{}),
+		term:     make(chan struct{}),
+	}
+	for i := range l.done {
+		l.done[i] = make(chan struct{})
+	}
+	l.head.Store(int64(head))
+	go l.loop()
+	return l
+}
+
+// loop processes the blocks of the chain in order, staying within the lookahead
+// window of the import head.
+func (l *blockLookahead) loop() {
+	defer close(l.term)
+
+	for i, block := range l.chain {
+		select {
+		case <-l.quit:
+			return
+		default:
+		}
+		// Wait until the block enters the lookahead window
+		for i > int(l.head.Load())+l.depth {
+			select {
+			case <-l.progress:
+			case <-l.quit:
+				return
+			}
+		}
+		// Skip the block if the import already caught up with it, unless it's
+		// the first one, which still needs warming while it executes.
+		head := int(l.head.Load())
+		if i < head || (i == head && i != l.start) {
+			continue
+		}
+		if i == head {
+			l.warmBlock(block, head)
+			continue
+		}
+		// Check the body against the header, deriving the transaction hashes
+		// needed for the receipts along the way.
+		for _, tx := range block.Transactions() {
+			tx.Hash()
+		}
+		if l.verify != nil {
+			l.errs[i] = l.verify(block)
+			close(l.done[i])
+		}
+
+		// Warm the state of valid blocks at the parent of the import head. The
+		// state is only approximate, but most of the accounts and trie paths
+		// touched by the upcoming blocks won't change in between.
+		if l.errs[i] == nil {
+			l.warmBlock(block, head)
+		}
+	}
+}
+
+// warmBlock pre-warms the state of the given block at the parent state of the
+// block at the import head, if warming is enabled.
+func (l *blockLookahead) warmBlock(block *types.Block, head int) {
+	if l.warm == nil {
+		return
+	}
+	root := l.root
+	if head > 0 {
+		root = l.chain[head-1].Root()
+	}
+	l.warm(block, root, &l.interrupt)
+}
+
+// advance notifies the pipeline that the import moved to the block at index,
+// sliding the lookahead window forward.
+func (l *blockLookahead) advance(index int) {
+	l.head.Store(int64(index))
+	select {
+	case l.progress <- struct{}{}:
+	default:
+	}
+}
+
+// result returns the body content verification result of the block at index,
+// or false if the pipeline didn't verify it (yet).
+func (l *blockLookahead) result(index int) (error, bool) {
+	select {
+	case <-l.done[index]:
+		return l.errs[index], true
+	default:
+		return nil, false
+	}
+}
+
+// stop aborts the pipeline and waits for its worker to exit.
+func (l *blockLookahead) stop() {
+	l.interrupt.Store(true)
+	close(l.quit)
+	<-l.term
+}
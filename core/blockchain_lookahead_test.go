@@ -0,0 +1,176 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the lookahead pipeline verifies and warms the blocks following the
+// import head, never running further ahead than its depth, and only warms the
+// first executing block.
+func TestBlockLookaheadWindow(t *testing.T) {
+	chain := make(types.Blocks, 10)
+	for i := range chain {
+		chain[i] = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i + 1)), Root: common.Hash{byte(i + 1)}})
+	}
+	var (
+		lock     sync.Mutex
+		verified []int
+		warmed   = make(map[int]common.Hash)
+		errBad   = errors.New("bad body")
+		parent   = common.Hash{0xff}
+	)
+	verify := func(block *types.Block) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		verified = append(verified, int(block.NumberU64()-1))
+		if block.NumberU64() == 3 {
+			return errBad
+		}
+		return nil
+	}
+	warm := func(block *types.Block, root common.Hash, interrupt *atomic.Bool) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		warmed[int(block.NumberU64()-1)] = root
+	}
+	l := newBlockLookahead(chain, parent, 0, 3, verify, warm)
+	defer l.stop()
+
+	// Wait for the initial window and ensure nothing beyond it is touched
+	for i := 1; i <= 3; i++ {
+		<-l.done[i]
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := l.result(4); ok {
+		t.Fatal("block beyond the lookahead window verified")
+	}
+	if _, ok := l.result(0); ok {
+		t.Fatal("import head verified")
+	}
+	if err, _ := l.result(2); !errors.Is(err, errBad) {
+		t.Fatalf("bad body error mismatch: have %v, want %v", err, errBad)
+	}
+	// Slide the window and ensure the new blocks are warmed at the right state
+	l.advance(2)
+	for i := 4; i <= 5; i++ {
+		<-l.done[i]
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := l.result(6); ok {
+		t.Fatal("block beyond the advanced lookahead window verified")
+	}
+	lock.Lock()
+	defer lock.Unlock()
+
+	if want := []int{1, 2, 3, 4, 5}; !slices.Equal(verified, want) {
+		t.Errorf("verified blocks mismatch: have %v, want %v", verified, want)
+	}
+	want := map[int]common.Hash{0: parent, 1: parent, 3: parent, 4: chain[1].Root(), 5: chain[1].Root()}
+	if !maps.Equal(warmed, want) {
+		t.Errorf("warmed blocks mismatch: have %v, want %v", warmed, want)
+	}
+}
+
+// Tests that stopping the lookahead pipeline interrupts a running state warming.
+func TestBlockLookaheadStop(t *testing.T) {
+	chain := make(types.Blocks, 4)
+	for i := range chain {
+		chain[i] = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i + 1))})
+	}
+	var (
+		started = make(chan struct{})
+		once    sync.Once
+	)
+	warm := func(block *types.Block, root common.Hash, interrupt *atomic.Bool) {
+		once.Do(func() { close(started) })
+		for !interrupt.Load() {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	l := newBlockLookahead(chain, common.Hash{}, 0, 2, func(*types.Block) error { return nil }, warm)
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		l.stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lookahead stop timed out")
+	}
+	if _, ok := l.result(2); ok {
+		t.Fatal("block verified after stop")
+	}
+}
+
+// Tests that a block with a body not matching its header is rejected during
+// import, with its content verified ahead by the lookahead pipeline.
+func TestInsertChainLookaheadBadBody(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.LatestSigner(params.TestChainConfig)
+		gspec  = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 8, func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.Address{0x01}, big.NewInt(1), params.TxGas, gen.header.BaseFee, nil), signer, key)
+		gen.AddTx(tx)
+	})
+	blocks[5] = blocks[5].WithBody(types.Body{Transactions: blocks[4].Transactions()})
+
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	n, err := chain.InsertChain(blocks)
+	if err == nil || !strings.Contains(err.Error(), "transaction root hash mismatch") {
+		t.Fatalf("bad body error mismatch: have %v", err)
+	}
+	if n != 5 {
+		t.Fatalf("failed block index mismatch: have %d, want %d", n, 5)
+	}
+	if head := chain.CurrentBlock().Number.Uint64(); head != 5 {
+		t.Fatalf("chain head mismatch: have %d, want %d", head, 5)
+	}
+}
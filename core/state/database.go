@@ -172,6 +172,16 @@ func NewDatabase(triedb *triedb.Database, snap *snapshot.Tree) *CachingDB {
 	}
 }
 
+// CodeCacheStats returns the usage statistics of the contract code cache.
+func (db *CachingDB) CodeCacheStats() lru.Stats {
+	return db.codeCache.Stats()
+}
+
+// CodeSizeCacheStats returns the usage statistics of the contract code size cache.
+func (db *CachingDB) CodeSizeCacheStats() lru.Stats {
+	return db.codeSizeCache.Stats()
+}
+
 // NewDatabaseForTesting is similar to NewDatabase, but it initializes the caching
 // db by using an ephemeral memory db with default config for testing.
 func NewDatabaseForTesting() *CachingDB {
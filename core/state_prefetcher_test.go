@@ -0,0 +1,229 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+func TestPrewarmCalldataAddresses(t *testing.T) {
+	var (
+		addr = common.HexToAddress("0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef")
+		data = hexutil.MustDecode("0xa9059cbb" + // transfer(address,uint256)
+			"000000000000000000000000deadbeefdeadbeefdeadbeefdeadbeefdeadbeef" +
+			"0000000000000000000000000000000000000000000000000de0b6b3a7640000")
+	)
+	if have, want := prewarmCalldataAddresses(data), []common.Address{addr}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("address mismatch: have %v, want %v", have, want)
+	}
+	if have := prewarmCalldataAddresses(data[:4]); len(have) != 0 {
+		t.Fatalf("unexpected addresses from selector only: %v", have)
+	}
+}
+
+// recordingDatabase is a state database recording the accounts and storage
+// slots loaded through its readers.
+type recordingDatabase struct {
+	state.Database
+
+	lock     sync.Mutex
+	accounts map[common.Address]bool
+	slots    map[common.Address]map[common.Hash]bool
+	onLoad   func() // Optional callback invoked on every account load
+}
+
+func newRecordingDatabase(db state.Database) *recordingDatabase {
+	return &recordingDatabase{
+		Database: db,
+		accounts: make(map[common.Address]bool),
+		slots:    make(map[common.Address]map[common.Hash]bool),
+	}
+}
+
+func (db *recordingDatabase) Reader(root common.Hash) (state.Reader, error) {
+	reader, err := db.Database.Reader(root)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingReader{Reader: reader, db: db}, nil
+}
+
+func (db *recordingDatabase) loaded(addr common.Address) bool {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	return db.accounts[addr]
+}
+
+func (db *recordingDatabase) loadedSlot(addr common.Address, slot common.Hash) bool {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	return db.slots[addr][slot]
+}
+
+type recordingReader struct {
+	state.Reader
+	db *recordingDatabase
+}
+
+func (r *recordingReader) Account(addr common.Address) (*types.StateAccount, error) {
+	r.db.lock.Lock()
+	r.db.accounts[addr] = true
+	onLoad := r.db.onLoad
+	r.db.lock.Unlock()
+
+	if onLoad != nil {
+		onLoad()
+	}
+	return r.Reader.Account(addr)
+}
+
+func (r *recordingReader) Storage(addr common.Address, slot common.Hash) (common.Hash, error) {
+	r.db.lock.Lock()
+	if r.db.slots[addr] == nil {
+		r.db.slots[addr] = make(map[common.Hash]bool)
+	}
+	r.db.slots[addr][slot] = true
+	r.db.lock.Unlock()
+
+	return r.Reader.Storage(addr, slot)
+}
+
+func (r *recordingReader) Copy() state.Reader {
+	return &recordingReader{Reader: r.Reader.Copy(), db: r.db}
+}
+
+func TestPrewarm(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		target = common.HexToAddress("0x1000000000000000000000000000000000000000")
+		holder = common.HexToAddress("0x2000000000000000000000000000000000000000")
+		slot   = common.HexToHash("0x01")
+		gspec  = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				sender: {Balance: big.NewInt(params.Ether)},
+				target: {Balance: big.NewInt(1), Storage: map[common.Hash]common.Hash{slot: common.HexToHash("0x02")}},
+			},
+		}
+		signer = types.LatestSigner(gspec.Config)
+		data   = append(hexutil.MustDecode("0xa9059cbb"), common.LeftPadBytes(holder.Bytes(), 32)...)
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 1, func(i int, b *BlockGen) {
+		tx := types.MustSignNewTx(key, signer, &types.AccessListTx{
+			ChainID:    gspec.Config.ChainID,
+			Nonce:      0,
+			To:         &target,
+			Gas:        50000,
+			GasPrice:   b.header.BaseFee,
+			Data:       data,
+			AccessList: types.AccessList{{Address: target, StorageKeys: []common.Hash{slot}}},
+		})
+		b.AddTx(tx)
+	})
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		tdb     = triedb.NewDatabase(db, triedb.HashDefaults)
+		genesis = gspec.MustCommit(db, tdb)
+	)
+	prefetcher := newStatePrefetcher(gspec.Config, nil)
+
+	sdb := newRecordingDatabase(state.NewDatabase(tdb, nil))
+	prefetcher.Prewarm(blocks[0], genesis.Root(), sdb, 4, nil)
+
+	for name, addr := range map[string]common.Address{"sender": sender, "recipient": target, "calldata": holder} {
+		if !sdb.loaded(addr) {
+			t.Errorf("%s account %x not loaded", name, addr)
+		}
+	}
+	if !sdb.loadedSlot(target, slot) {
+		t.Errorf("access list slot %x of %x not loaded", slot, target)
+	}
+	// An interrupted pre-warm must bail out without touching the state
+	var interrupt atomic.Bool
+	interrupt.Store(true)
+
+	sdb = newRecordingDatabase(state.NewDatabase(tdb, nil))
+	prefetcher.Prewarm(blocks[0], genesis.Root(), sdb, 4, &interrupt)
+	if len(sdb.accounts) != 0 || len(sdb.slots) != 0 {
+		t.Errorf("interrupted pre-warm loaded state: %d accounts, %d storage owners", len(sdb.accounts), len(sdb.slots))
+	}
+}
+
+func TestPrewarmInterrupt(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		gspec  = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer     = types.LatestSigner(gspec.Config)
+		recipients []common.Address
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 1, func(i int, b *BlockGen) {
+		for nonce := uint64(0); nonce < 8; nonce++ {
+			to := common.BigToAddress(new(big.Int).SetUint64(0x1000 + nonce))
+			recipients = append(recipients, to)
+
+			b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+				Nonce:    nonce,
+				To:       &to,
+				Value:    big.NewInt(1),
+				Gas:      params.TxGas,
+				GasPrice: b.header.BaseFee,
+			}))
+		}
+	})
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		tdb     = triedb.NewDatabase(db, triedb.HashDefaults)
+		genesis = gspec.MustCommit(db, tdb)
+		sdb     = newRecordingDatabase(state.NewDatabase(tdb, nil))
+
+		interrupt atomic.Bool
+	)
+	// Raise the interrupt as soon as the first account is loaded, the remaining
+	// transactions must be skipped.
+	sdb.onLoad = func() { interrupt.Store(true) }
+
+	newStatePrefetcher(gspec.Config, nil).Prewarm(blocks[0], genesis.Root(), sdb, 1, &interrupt)
+
+	var loaded int
+	for _, addr := range recipients {
+		if sdb.loaded(addr) {
+			loaded++
+		}
+	}
+	if loaded > 1 {
+		t.Fatalf("interrupted pre-warm loaded %d of %d recipients", loaded, len(recipients))
+	}
+}
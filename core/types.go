@@ -19,6 +19,7 @@ package core
 import (
 	"sync/atomic"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -41,6 +42,11 @@ type Prefetcher interface {
 	// the transaction messages using the statedb, but any changes are discarded. The
 	// only goal is to pre-cache transaction signatures and state trie nodes.
 	Prefetch(block *types.Block, statedb *state.StateDB, cfg vm.Config, interrupt *atomic.Bool)
+
+	// Prewarm loads the accounts and storage slots statically referenced by the
+	// transactions of a block (senders, recipients, access lists and calldata)
+	// into the state caches in parallel, without executing anything.
+	Prewarm(block *types.Block, root common.Hash, db state.Database, threads int, interrupt *atomic.Bool)
 }
 
 // Processor is an interface for processing blocks using a given initial state.
@@ -174,6 +174,12 @@ func init() {
 }
 
 func activePrecompiledContracts(rules params.Rules) PrecompiledContracts {
+	return registeredPrecompiledContracts(rules, forkPrecompiledContracts(rules))
+}
+
+// forkPrecompiledContracts returns the built-in precompiled contracts of the
+// fork active with the given rules.
+func forkPrecompiledContracts(rules params.Rules) PrecompiledContracts {
 	switch {
 	case rules.IsVerkle:
 		return PrecompiledContractsVerkle
@@ -199,6 +205,12 @@ func ActivePrecompiledContracts(rules params.Rules) PrecompiledContracts {
 
 // ActivePrecompiles returns the precompile addresses enabled with the current configuration.
 func ActivePrecompiles(rules params.Rules) []common.Address {
+	return registeredPrecompiles(rules, forkPrecompiles(rules))
+}
+
+// forkPrecompiles returns the addresses of the built-in precompiled contracts
+// of the fork active with the given rules.
+func forkPrecompiles(rules params.Rules) []common.Address {
 	switch {
 	case rules.IsPrague:
 		return PrecompiledAddressesPrague
@@ -0,0 +1,101 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// BMTVerifyAddress is the address of the binary Merkle tree inclusion proof
+// verification precompile, enabled from the BMTVerifyBlock fork.
+var BMTVerifyAddress = common.BytesToAddress([]byte{0x02, 0x00})
+
+var (
+	errBMTVerifyInputLength = errors.New("invalid bmt proof input length")
+	errBMTVerifyProofDepth  = errors.New("bmt proof exceeds maximum depth")
+	errBMTVerifyIndex       = errors.New("bmt segment index out of range")
+)
+
+func init() {
+	MustRegisterPrecompile(PrecompileRegistration{
+		Name:        "bmtVerify",
+		Address:     BMTVerifyAddress,
+		Active:      func(rules params.Rules) bool { return rules.IsBMTVerify },
+		RequiredGas: new(bmtVerify).RequiredGas,
+		Run:         new(bmtVerify).Run,
+	})
+}
+
+// bmtVerify implements inclusion proof verification for a plain binary Merkle
+// tree of arbitrary depth. The input is the concatenation of
+//
+//	root    [32]byte   - root of the binary Merkle tree
+//	segment [32]byte   - leaf segment whose inclusion is proven
+//	index   uint256    - position of the segment among the leaves
+//	proof   [][32]byte - sibling hashes from the leaf level upwards
+//
+// Nodes are hashed as keccak256(left || right) and the root is the topmost node
+// itself. Note this is not the swarm BMT: there is no span prefix and the depth
+// is not fixed to 128 segments, so swarm chunk addresses cannot be verified
+// directly. The output is a 32 byte word holding 1 if the proof is valid and 0
+// otherwise. Malformed input fails the call.
+type bmtVerify struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *bmtVerify) RequiredGas(input []byte) uint64 {
+	var levels uint64
+	if len(input) > 96 {
+		levels = uint64(len(input)-96) / 32
+	}
+	return params.BMTVerifyBaseGas + levels*params.BMTVerifyPerLevelGas
+}
+
+func (c *bmtVerify) Run(input []byte) ([]byte, error) {
+	if len(input) < 96 || (len(input)-96)%32 != 0 {
+		return nil, errBMTVerifyInputLength
+	}
+	depth := (len(input) - 96) / 32
+	if depth > params.BMTVerifyMaxDepth {
+		return nil, errBMTVerifyProofDepth
+	}
+	var (
+		root  = input[:32]
+		hash  = common.CopyBytes(input[32:64])
+		index = new(big.Int).SetBytes(input[64:96])
+		proof = input[96:]
+	)
+	if index.BitLen() > depth {
+		return nil, errBMTVerifyIndex
+	}
+	for level := 0; level < depth; level++ {
+		sibling := proof[level*32 : (level+1)*32]
+		if index.Bit(level) == 0 {
+			hash = crypto.Keccak256(hash, sibling)
+		} else {
+			hash = crypto.Keccak256(sibling, hash)
+		}
+	}
+	if common.BytesToHash(hash) != common.BytesToHash(root) {
+		return common.LeftPadBytes(nil, 32), nil
+	}
+	return common.LeftPadBytes([]byte{1}, 32), nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// bmtProofInput builds a binary Merkle tree over the given leaves and returns
+// the precompile input proving the inclusion of the leaf at index.
+func bmtProofInput(leaves [][]byte, index int) []byte {
+	var (
+		level = leaves
+		proof []byte
+		pos   = index
+	)
+	for len(level) > 1 {
+		proof = append(proof, level[pos^1]...)
+
+		next := make([][]byte, len(level)/2)
+		for i := range next {
+			next[i] = crypto.Keccak256(level[2*i], level[2*i+1])
+		}
+		level, pos = next, pos/2
+	}
+	input := append(common.CopyBytes(level[0]), leaves[index]...)
+	input = append(input, common.LeftPadBytes([]byte{byte(index)}, 32)...)
+	return append(input, proof...)
+}
+
+func TestPrecompiledBMTVerify(t *testing.T) {
+	leaves := make([][]byte, 8)
+	for i := range leaves {
+		leaves[i] = crypto.Keccak256([]byte{byte(i)})
+	}
+	var (
+		valid   = common.Bytes2Hex(common.LeftPadBytes([]byte{1}, 32))
+		invalid = common.Bytes2Hex(make([]byte, 32))
+		gas     = params.BMTVerifyBaseGas + 3*params.BMTVerifyPerLevelGas
+	)
+	for i := range leaves {
+		testPrecompiled("200", precompiledTest{
+			Input:    common.Bytes2Hex(bmtProofInput(leaves, i)),
+			Expected: valid,
+			Gas:      gas,
+			Name:     fmt.Sprintf("valid-%d", i),
+		}, t)
+	}
+	// Tampering with any part of the proof must invalidate it
+	for _, offset := range []int{0, 32, 64 + 31, 96, 96 + 64} {
+		input := bmtProofInput(leaves, 5)
+		input[offset] ^= 0x01
+		testPrecompiled("200", precompiledTest{
+			Input:    common.Bytes2Hex(input),
+			Expected: invalid,
+			Gas:      gas,
+			Name:     fmt.Sprintf("tampered-%d", offset),
+		}, t)
+	}
+	// A single leaf tree is its own root
+	testPrecompiled("200", precompiledTest{
+		Input:    common.Bytes2Hex(append(append(common.CopyBytes(leaves[0]), leaves[0]...), make([]byte, 32)...)),
+		Expected: valid,
+		Gas:      params.BMTVerifyBaseGas,
+		Name:     "single-leaf",
+	}, t)
+}
+
+func TestPrecompiledBMTVerifyMalformedInput(t *testing.T) {
+	index := common.LeftPadBytes([]byte{8}, 32)
+	tests := []precompiledFailureTest{
+		{
+			Input:         "",
+			ExpectedError: errBMTVerifyInputLength.Error(),
+			Name:          "empty input",
+		},
+		{
+			Input:         common.Bytes2Hex(make([]byte, 97)),
+			ExpectedError: errBMTVerifyInputLength.Error(),
+			Name:          "partial proof node",
+		},
+		{
+			Input:         common.Bytes2Hex(make([]byte, 96+32*(params.BMTVerifyMaxDepth+1))),
+			ExpectedError: errBMTVerifyProofDepth.Error(),
+			Name:          "proof too deep",
+		},
+		{
+			Input:         common.Bytes2Hex(append(append(make([]byte, 64), index...), make([]byte, 3*32)...)),
+			ExpectedError: errBMTVerifyIndex.Error(),
+			Name:          "index out of range",
+		},
+	}
+	for _, test := range tests {
+		testPrecompiledFailure("200", test, t)
+	}
+}
+
+func TestPrecompiledBMTVerifyActivation(t *testing.T) {
+	if _, ok := activePrecompiledContracts(params.Rules{IsCancun: true})[BMTVerifyAddress]; ok {
+		t.Fatal("bmt verification precompile active before its fork")
+	}
+	if _, ok := activePrecompiledContracts(params.Rules{IsBMTVerify: true})[BMTVerifyAddress]; !ok {
+		t.Fatal("bmt verification precompile inactive after its fork")
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// PrecompileRegistration describes an additional precompiled contract which is
+// enabled on top of the built-in set once its activation fork is reached.
+type PrecompileRegistration struct {
+	Name    string         // Human readable name, used in errors and logs
+	Address common.Address // Address the contract is reachable at
+
+	// Active reports whether the contract is enabled under the given chain
+	// rules, typically by checking the fork flag it was scheduled with. Contracts
+	// activated at a height without a dedicated fork flag can check the block
+	// number and timestamp of the rules instead; these are nil and zero in rules
+	// not derived from a block, which must be treated as inactive.
+	Active func(rules params.Rules) bool
+
+	RequiredGas func(input []byte) uint64          // Deterministic gas cost of the call
+	Run         func(input []byte) ([]byte, error) // Contract implementation
+}
+
+var (
+	precompileRegistryLock sync.RWMutex
+	precompileRegistry     []*PrecompileRegistration
+)
+
+// builtinPrecompileSets are the built-in precompiled contracts of every fork,
+// all of which registered contracts must not collide with.
+var builtinPrecompileSets = []PrecompiledContracts{
+	PrecompiledContractsHomestead,
+	PrecompiledContractsByzantium,
+	PrecompiledContractsIstanbul,
+	PrecompiledContractsBerlin,
+	PrecompiledContractsCancun,
+	PrecompiledContractsPrague,
+	PrecompiledContractsBLS,
+	PrecompiledContractsVerkle,
+}
+
+// RegisterPrecompile adds an additional precompiled contract to the EVM. The
+// registration is global and is expected to be done during initialization,
+// before any EVM is created. Registering a contract at the address of a
+// built-in precompile of any fork or of an already registered one is an error.
+func RegisterPrecompile(p PrecompileRegistration) error {
+	if p.Active == nil || p.RequiredGas == nil || p.Run == nil {
+		return fmt.Errorf("precompile %q: incomplete registration", p.Name)
+	}
+	for _, builtin := range builtinPrecompileSets {
+		if _, ok := builtin[p.Address]; ok {
+			return fmt.Errorf("precompile %q: address %v taken by a built-in precompile", p.Name, p.Address)
+		}
+	}
+	precompileRegistryLock.Lock()
+	defer precompileRegistryLock.Unlock()
+
+	for _, have := range precompileRegistry {
+		if have.Address == p.Address {
+			return fmt.Errorf("precompile %q: address %v taken by precompile %q", p.Name, p.Address, have.Name)
+		}
+	}
+	precompileRegistry = append(precompileRegistry, &p)
+	return nil
+}
+
+// MustRegisterPrecompile is like RegisterPrecompile, but panics on error.
+func MustRegisterPrecompile(p PrecompileRegistration) {
+	if err := RegisterPrecompile(p); err != nil {
+		panic(err)
+	}
+}
+
+// errUnregisteredPrecompile is returned when unregistering an unknown address.
+var errUnregisteredPrecompile = errors.New("no precompile registered at address")
+
+// UnregisterPrecompile removes a previously registered precompiled contract.
+func UnregisterPrecompile(addr common.Address) error {
+	precompileRegistryLock.Lock()
+	defer precompileRegistryLock.Unlock()
+
+	for i, have := range precompileRegistry {
+		if have.Address == addr {
+			precompileRegistry = append(precompileRegistry[:i:i], precompileRegistry[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %v", errUnregisteredPrecompile, addr)
+}
+
+// RegisteredPrecompiles returns the additional precompiled contracts enabled
+// under the given chain rules.
+func RegisteredPrecompiles(rules params.Rules) []PrecompileRegistration {
+	precompileRegistryLock.RLock()
+	defer precompileRegistryLock.RUnlock()
+
+	var active []PrecompileRegistration
+	for _, p := range precompileRegistry {
+		if p.Active(rules) {
+			active = append(active, *p)
+		}
+	}
+	return active
+}
+
+// registeredPrecompiledContracts extends the built-in precompiled contracts
+// with the registered ones active under the given rules. The built-in set is
+// returned as is if there are no additional contracts to avoid copying it for
+// every EVM.
+func registeredPrecompiledContracts(rules params.Rules, builtin PrecompiledContracts) PrecompiledContracts {
+	active := RegisteredPrecompiles(rules)
+	if len(active) == 0 {
+		return builtin
+	}
+	contracts := maps.Clone(builtin)
+	for _, p := range active {
+		contracts[p.Address] = &registeredPrecompile{gas: p.RequiredGas, run: p.Run}
+	}
+	return contracts
+}
+
+// registeredPrecompiles extends the built-in precompile addresses with the
+// registered ones active under the given rules.
+func registeredPrecompiles(rules params.Rules, builtin []common.Address) []common.Address {
+	active := RegisteredPrecompiles(rules)
+	if len(active) == 0 {
+		return builtin
+	}
+	addrs := make([]common.Address, 0, len(builtin)+len(active))
+	addrs = append(addrs, builtin...)
+	for _, p := range active {
+		addrs = append(addrs, p.Address)
+	}
+	return addrs
+}
+
+// registeredPrecompile adapts a registration to the PrecompiledContract interface.
+type registeredPrecompile struct {
+	gas func(input []byte) uint64
+	run func(input []byte) ([]byte, error)
+}
+
+func (c *registeredPrecompile) RequiredGas(input []byte) uint64 {
+	return c.gas(input)
+}
+
+func (c *registeredPrecompile) Run(input []byte) ([]byte, error) {
+	return c.run(input)
+}
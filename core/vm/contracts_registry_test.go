@@ -0,0 +1,120 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestPrecompileRegistry(t *testing.T) {
+	addr := common.BytesToAddress([]byte{0x01, 0x00})
+	echo := PrecompileRegistration{
+		Name:        "echo",
+		Address:     addr,
+		Active:      func(rules params.Rules) bool { return rules.IsCancun },
+		RequiredGas: func(input []byte) uint64 { return uint64(len(input)) },
+		Run:         func(input []byte) ([]byte, error) { return common.CopyBytes(input), nil },
+	}
+	if err := RegisterPrecompile(echo); err != nil {
+		t.Fatalf("failed to register precompile: %v", err)
+	}
+	defer UnregisterPrecompile(addr)
+
+	// Conflicting registrations must be rejected
+	if err := RegisterPrecompile(echo); err == nil {
+		t.Fatal("duplicate registration accepted")
+	}
+	for i, set := range builtinPrecompileSets {
+		for addr := range set {
+			builtin := echo
+			builtin.Address = addr
+			if err := RegisterPrecompile(builtin); err == nil {
+				UnregisterPrecompile(addr)
+				t.Fatalf("set %d: registration over built-in precompile %v accepted", i, addr)
+			}
+		}
+	}
+	// The contract must only be active from its activation fork onwards
+	var (
+		berlin = params.Rules{IsBerlin: true}
+		cancun = params.Rules{IsBerlin: true, IsCancun: true}
+	)
+	if _, ok := activePrecompiledContracts(berlin)[addr]; ok {
+		t.Fatal("precompile active before its fork")
+	}
+	if slices.Contains(ActivePrecompiles(berlin), addr) {
+		t.Fatal("precompile address reported before its fork")
+	}
+	p, ok := activePrecompiledContracts(cancun)[addr]
+	if !ok {
+		t.Fatal("precompile inactive after its fork")
+	}
+	if !slices.Contains(ActivePrecompiles(cancun), addr) {
+		t.Fatal("precompile address missing after its fork")
+	}
+	if _, ok := PrecompiledContractsCancun[addr]; ok {
+		t.Fatal("built-in precompile set modified")
+	}
+	out, gas, err := RunPrecompiledContract(p, []byte{1, 2, 3}, 10, nil)
+	if err != nil {
+		t.Fatalf("failed to run precompile: %v", err)
+	}
+	if !bytes.Equal(out, []byte{1, 2, 3}) || gas != 7 {
+		t.Fatalf("precompile result mismatch: have %x (gas %d), want 010203 (gas 7)", out, gas)
+	}
+	// Unregistering must disable the contract again
+	if err := UnregisterPrecompile(addr); err != nil {
+		t.Fatalf("failed to unregister precompile: %v", err)
+	}
+	if _, ok := activePrecompiledContracts(cancun)[addr]; ok {
+		t.Fatal("precompile active after unregistering")
+	}
+}
+
+func TestPrecompileRegistryBlockActivation(t *testing.T) {
+	addr := common.BytesToAddress([]byte{0x01, 0x01})
+	MustRegisterPrecompile(PrecompileRegistration{
+		Name:    "height",
+		Address: addr,
+		Active: func(rules params.Rules) bool {
+			return rules.BlockNumber != nil && rules.BlockNumber.Uint64() >= 10
+		},
+		RequiredGas: func(input []byte) uint64 { return 0 },
+		Run:         func(input []byte) ([]byte, error) { return nil, nil },
+	})
+	defer UnregisterPrecompile(addr)
+
+	for _, tt := range []struct {
+		number uint64
+		active bool
+	}{{9, false}, {10, true}} {
+		rules := params.TestChainConfig.Rules(new(big.Int).SetUint64(tt.number), true, 0)
+		if active := slices.Contains(ActivePrecompiles(rules), addr); active != tt.active {
+			t.Errorf("block %d: activation mismatch: have %v, want %v", tt.number, active, tt.active)
+		}
+	}
+	// Hand-crafted rules carry no block context
+	if slices.Contains(ActivePrecompiles(params.Rules{IsCancun: true}), addr) {
+		t.Error("precompile active without block context")
+	}
+}
@@ -58,6 +58,19 @@ func ValidEip(eipNum int) bool {
 	_, ok := activators[eipNum]
 	return ok
 }
+
+// scheduledEIPs returns the EIPs activated by the chain config ahead of the
+// fork bundling them, which need to be enabled on top of the fork's jump table.
+func scheduledEIPs(rules params.Rules) []int {
+	var eips []int
+	if rules.IsEIP2200 && !rules.IsIstanbul {
+		eips = append(eips, 2200)
+	}
+	if rules.IsEIP2929 && !rules.IsBerlin {
+		eips = append(eips, 2929)
+	}
+	return eips
+}
 func ActivateableEips() []string {
 	var nums []string
 	for k := range activators {
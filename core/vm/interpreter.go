@@ -134,11 +134,17 @@ func NewEVMInterpreter(evm *EVM) *EVMInterpreter {
 	default:
 		table = &frontierInstructionSet
 	}
-	var extraEips []int
-	if len(evm.Config.ExtraEips) > 0 {
+	var (
+		scheduled = scheduledEIPs(evm.chainRules)
+		extraEips []int
+	)
+	if len(scheduled) > 0 || len(evm.Config.ExtraEips) > 0 {
 		// Deep-copy jumptable to prevent modification of opcodes in other tables
 		table = copyJumpTable(table)
 	}
+	for _, eip := range scheduled {
+		EnableEIP(eip, table)
+	}
 	for _, eip := range evm.Config.ExtraEips {
 		if err := EnableEIP(eip, table); err != nil {
 			// Disable it, so caller can check if it's activated or not
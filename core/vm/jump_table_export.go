@@ -23,8 +23,18 @@ import (
 )
 
 // LookupInstructionSet returns the instruction set for the fork configured by
-// the rules.
+// the rules, including any EIPs scheduled ahead of their fork.
 func LookupInstructionSet(rules params.Rules) (JumpTable, error) {
+	jt, err := lookupForkInstructionSet(rules)
+	for _, eip := range scheduledEIPs(rules) {
+		EnableEIP(eip, &jt)
+	}
+	return jt, err
+}
+
+// lookupForkInstructionSet returns the instruction set for the fork configured
+// by the rules.
+func lookupForkInstructionSet(rules params.Rules) (JumpTable, error) {
 	switch {
 	case rules.IsVerkle:
 		return newCancunInstructionSet(), errors.New("verkle-fork not defined yet")
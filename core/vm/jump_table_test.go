@@ -19,6 +19,7 @@ package vm
 import (
 	"testing"
 
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,3 +34,26 @@ func TestJumpTableCopy(t *testing.T) {
 	require.Equal(t, uint64(100), deepCopy[SLOAD].constantGas)
 	require.Equal(t, uint64(0), tbl[SLOAD].constantGas)
 }
+
+// TestScheduledEIPs tests that EIPs scheduled ahead of their fork are enabled
+// on top of the fork's instruction set.
+func TestScheduledEIPs(t *testing.T) {
+	rules := params.Rules{IsHomestead: true, IsEIP150: true, IsEIP158: true, IsByzantium: true, IsConstantinople: true, IsPetersburg: true}
+
+	tbl, err := LookupInstructionSet(rules)
+	require.NoError(t, err)
+	require.Equal(t, params.SloadGasEIP150, tbl[SLOAD].constantGas)
+
+	rules.IsEIP2200 = true
+	tbl, err = LookupInstructionSet(rules)
+	require.NoError(t, err)
+	require.Equal(t, params.SloadGasEIP2200, tbl[SLOAD].constantGas)
+
+	rules.IsEIP2929 = true
+	tbl, err = LookupInstructionSet(rules)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), tbl[SLOAD].constantGas)
+
+	// The shared instruction set must not be modified
+	require.Equal(t, params.SloadGasEIP150, constantinopleInstructionSet[SLOAD].constantGas)
+}
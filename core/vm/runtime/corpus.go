@@ -0,0 +1,68 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// DefaultCorpusGasLimit is the gas limit of the calls of a corpus entry which
+// doesn't specify one.
+const DefaultCorpusGasLimit = 10_000_000
+
+// CorpusEntry is a contract along with the calldata to execute it with, the
+// unit of the bytecode corpora used by the execution benchmarks. Corpora are
+// JSON files holding a list of entries, written by hand or extracted from the
+// chain with "geth export-corpus".
+type CorpusEntry struct {
+	Name     string          `json:"name"`
+	Category string          `json:"category"`
+	Code     hexutil.Bytes   `json:"code"`
+	Calls    []hexutil.Bytes `json:"calls"`
+	GasLimit uint64          `json:"gasLimit,omitempty"` // Per call, defaults to DefaultCorpusGasLimit
+}
+
+// LoadCorpus loads the entries of all corpora in the given directory.
+func LoadCorpus(dir string) ([]*CorpusEntry, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var entries []*CorpusEntry
+	for _, file := range files {
+		blob, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var list []*CorpusEntry
+		if err := json.Unmarshal(blob, &list); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		for _, entry := range list {
+			if entry.GasLimit == 0 {
+				entry.GasLimit = DefaultCorpusGasLimit
+			}
+		}
+		entries = append(entries, list...)
+	}
+	return entries, nil
+}
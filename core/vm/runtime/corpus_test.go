@@ -0,0 +1,149 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// corpusDir is the directory holding the bytecode corpora to benchmark. Every
+// JSON file in it contains a list of corpus entries.
+var corpusDir = flag.String("corpus", filepath.Join("testdata", "corpus"), "directory of bytecode corpora to benchmark")
+
+// corpusRunner executes the calls of a corpus entry against a fresh contract,
+// reverting any state changes after each call.
+type corpusRunner struct {
+	entry    *CorpusEntry
+	cfg      *Config
+	contract common.Address
+}
+
+func newCorpusRunner(entry *CorpusEntry) *corpusRunner {
+	cfg := new(Config)
+	setDefaults(cfg)
+	cfg.State, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	cfg.GasLimit = entry.GasLimit
+
+	contract := common.BytesToAddress([]byte("contract"))
+	cfg.State.CreateAccount(contract)
+	cfg.State.SetCode(contract, entry.Code)
+
+	return &corpusRunner{entry: entry, cfg: cfg, contract: contract}
+}
+
+// run executes all calls of the entry once, returning the gas used and the
+// number of failed calls. Calls extracted from the chain run without the state
+// of the original contract, so they may fail halfway; their execution is still
+// measured.
+func (r *corpusRunner) run() (uint64, int) {
+	var (
+		used   uint64
+		failed int
+	)
+	for _, input := range r.entry.Calls {
+		snap := r.cfg.State.Snapshot()
+		_, left, err := Call(r.contract, input, r.cfg)
+		r.cfg.State.RevertToSnapshot(snap)
+		if err != nil {
+			failed++
+		}
+		used += r.cfg.GasLimit - left
+	}
+	return used, failed
+}
+
+// TestCorpus checks that the checked in corpus entries execute successfully,
+// so the benchmarks measure what they claim.
+func TestCorpus(t *testing.T) {
+	entries, err := LoadCorpus(filepath.Join("testdata", "corpus"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("empty corpus")
+	}
+	for _, entry := range entries {
+		if used, failed := newCorpusRunner(entry).run(); failed > 0 {
+			t.Errorf("%s/%s: %d calls failed", entry.Category, entry.Name, failed)
+		} else if used == 0 {
+			t.Errorf("%s/%s: no gas used", entry.Category, entry.Name)
+		}
+	}
+}
+
+// BenchmarkCorpus runs the calls of the corpus entries through the interpreter,
+// reporting the execution speed in gas per second along with the allocations.
+// Every entry is benchmarked as <category>/<name>, and all entries of a category
+// together as <category>/all, so a subset can be selected with -bench, e.g.
+// -bench 'Corpus/erc20/all'. A different corpus, such as one extracted from the
+// chain with "geth export-corpus", can be used with -corpus.
+func BenchmarkCorpus(b *testing.B) {
+	entries, err := LoadCorpus(*corpusDir)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var (
+		categories []string
+		grouped    = make(map[string][]*CorpusEntry)
+	)
+	for _, entry := range entries {
+		if _, ok := grouped[entry.Category]; !ok {
+			categories = append(categories, entry.Category)
+		}
+		grouped[entry.Category] = append(grouped[entry.Category], entry)
+	}
+	for _, category := range categories {
+		b.Run(category+"/all", func(b *testing.B) {
+			benchmarkCorpusEntries(b, grouped[category])
+		})
+		for _, entry := range grouped[category] {
+			b.Run(category+"/"+entry.Name, func(b *testing.B) {
+				benchmarkCorpusEntries(b, []*CorpusEntry{entry})
+			})
+		}
+	}
+}
+
+// benchmarkCorpusEntries measures a single execution of all calls of the given
+// entries as one operation.
+func benchmarkCorpusEntries(b *testing.B, entries []*CorpusEntry) {
+	runners := make([]*corpusRunner, len(entries))
+	for i, entry := range entries {
+		runners[i] = newCorpusRunner(entry)
+	}
+	var (
+		gas    uint64
+		failed int
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, runner := range runners {
+			used, fails := runner.run()
+			gas += used
+			failed += fails
+		}
+	}
+	b.ReportMetric(float64(gas)/b.Elapsed().Seconds(), "gas/s")
+	b.ReportMetric(float64(failed)/float64(b.N), "failed/op")
+}
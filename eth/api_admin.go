@@ -18,14 +18,21 @@ package eth
 
 import (
 	"compress/gzip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/version"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -141,3 +148,44 @@ func (api *AdminAPI) ImportChain(file string) (bool, error) {
 	}
 	return true, nil
 }
+
+// NodeInfoExtended is the build and chain fork identity of a running node,
+// allowing tooling to verify that it is talking to a compatible node before
+// relying on fork specific behavior.
+type NodeInfoExtended struct {
+	Version    string                  `json:"version"`    // Client version including build metadata
+	Commit     string                  `json:"commit"`     // VCS commit the binary was built from, if known
+	NetworkID  uint64                  `json:"networkId"`  // Ethereum network identifier
+	ChainID    *big.Int                `json:"chainId"`    // Chain identifier used for replay protection
+	Genesis    common.Hash             `json:"genesis"`    // Hash of the genesis block
+	ConfigHash common.Hash             `json:"configHash"` // Keccak256 hash of the JSON encoded chain config
+	ForkID     forkid.ID               `json:"forkId"`     // EIP-2124 fork identifier at the current head
+	Forks      []params.ForkActivation `json:"forks"`      // Scheduled forks in activation order
+	EIPs       []params.EIPRange       `json:"eips"`       // EIP set in effect per activation range
+}
+
+// NodeInfoExtended retrieves the build information and the fork identity of
+// the chain the node is running.
+func (api *AdminAPI) NodeInfoExtended() (*NodeInfoExtended, error) {
+	var (
+		chain  = api.eth.BlockChain()
+		config = chain.Config()
+		head   = chain.CurrentHeader()
+	)
+	blob, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	version, commit := version.Info()
+	return &NodeInfoExtended{
+		Version:    version,
+		Commit:     commit,
+		NetworkID:  api.eth.networkID,
+		ChainID:    config.ChainID,
+		Genesis:    chain.Genesis().Hash(),
+		ConfigHash: crypto.Keccak256Hash(blob),
+		ForkID:     forkid.NewID(config, chain.Genesis(), head.Number.Uint64(), head.Time),
+		Forks:      config.ScheduledForks(),
+		EIPs:       config.ScheduledEIPs(),
+	}, nil
+}
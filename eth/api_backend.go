@@ -325,6 +325,10 @@ func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash)
 	return true, tx, lookup.BlockHash, lookup.BlockIndex, lookup.Index, nil
 }
 
+func (b *EthAPIBackend) GetPoolTransactionStatus(hash common.Hash) txpool.TxStatus {
+	return b.eth.txPool.Status(hash)
+}
+
 func (b *EthAPIBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
 	return b.eth.txPool.Nonce(addr), nil
 }
@@ -381,6 +385,10 @@ func (b *EthAPIBackend) EventMux() *event.TypeMux {
 	return b.eth.EventMux()
 }
 
+func (b *EthAPIBackend) CompactDatabase(start, end []byte) error {
+	return b.eth.compactor.compactRange(start, end)
+}
+
 func (b *EthAPIBackend) AccountManager() *accounts.Manager {
 	return b.eth.AccountManager()
 }
@@ -401,6 +409,10 @@ func (b *EthAPIBackend) RPCEVMTimeout() time.Duration {
 	return b.eth.config.RPCEVMTimeout
 }
 
+func (b *EthAPIBackend) RPCMethodLimits(method string) (uint64, time.Duration) {
+	return b.eth.config.MethodLimits(method)
+}
+
 func (b *EthAPIBackend) RPCTxFeeCap() float64 {
 	return b.eth.config.RPCTxFeeCap
 }
@@ -17,6 +17,7 @@
 package eth
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -28,6 +29,9 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers/live"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/caches"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -443,3 +447,87 @@ func (api *DebugAPI) GetTrieFlushInterval() (string, error) {
 	}
 	return api.eth.blockchain.GetTrieFlushInterval().String(), nil
 }
+
+// CompactDatabase compacts the given key range of the chain database. A missing
+// start denotes the beginning of the key space, a missing end its end. Only one
+// compaction may run at a time; its progress can be followed through
+// CompactionProgress.
+func (api *DebugAPI) CompactDatabase(start, end *hexutil.Bytes) error {
+	var from, to []byte
+	if start != nil {
+		from = *start
+	}
+	if end != nil {
+		to = *end
+	}
+	if from != nil && to != nil && bytes.Compare(from, to) >= 0 {
+		return errors.New("start key must be before end key")
+	}
+	return api.eth.compactor.compactRange(from, to)
+}
+
+// CompactionProgress returns the progress of the running database compaction,
+// or nil if there is none in progress.
+func (api *DebugAPI) CompactionProgress() *CompactionProgress {
+	return api.eth.compactor.status()
+}
+
+// Caches returns the usage statistics of the node's registered in-memory caches,
+// keyed by cache name.
+func (api *DebugAPI) Caches() map[string]caches.Stats {
+	return caches.Collect()
+}
+
+// accountChangesBuffer is the number of blocks buffered for an account changes
+// subscriber before the oldest ones are dropped.
+const accountChangesBuffer = 128
+
+// AccountChanges creates a subscription publishing the net balance, nonce and
+// code changes of the accounts touched by every processed block. It requires
+// the accountdiff live tracer to be enabled.
+func (api *DebugAPI) AccountChanges(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if !live.AccountDiffEnabled() {
+		return nil, errors.New("account diff tracer not enabled (run with --vmtrace accountdiff)")
+	}
+	var (
+		rpcSub  = notifier.CreateSubscription()
+		changes = make(chan *live.AccountChanges)
+		sub     = event.SubscribeBuffered("debug/accountchanges", live.SubscribeAccountChanges, changes, accountChangesBuffer)
+	)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case block := <-changes:
+				notifier.Notify(rpcSub.ID, block)
+			case <-rpcSub.Err():
+				return
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// GetAccountChanges retrieves the net balance, nonce and code changes of the
+// accounts touched by the given block from the index of the accountdiff live
+// tracer. It returns nil if the block was processed while the index was not
+// running.
+func (api *DebugAPI) GetAccountChanges(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*live.AccountChanges, error) {
+	if !live.AccountDiffEnabled() {
+		return nil, errors.New("account diff tracer not enabled (run with --vmtrace accountdiff)")
+	}
+	header, err := api.eth.APIBackend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("block not found")
+	}
+	return live.ReadAccountChanges(header.Number.Uint64(), header.Hash())
+}
@@ -83,6 +83,8 @@ type Ethereum struct {
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
 	closeBloomHandler chan struct{}
 
+	compactor *compactor // Database compaction scheduler
+
 	APIBackend *EthAPIBackend
 
 	miner    *miner.Miner
@@ -105,6 +107,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if !config.SyncMode.IsValid() {
 		return nil, fmt.Errorf("invalid sync mode %d", config.SyncMode)
 	}
+	if err := config.ValidateRPCMethodLimits(); err != nil {
+		return nil, err
+	}
 	if config.Miner.GasPrice == nil || config.Miner.GasPrice.Sign() <= 0 {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", ethconfig.Defaults.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(ethconfig.Defaults.Miner.GasPrice)
@@ -251,6 +256,8 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		return nil, err
 	}
 
+	eth.compactor = newCompactor(chainDb, eth.blockchain, eth.Synced, config.DatabaseCompactionInterval)
+
 	eth.miner = miner.New(eth, config.Miner, eth.engine)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
@@ -360,6 +367,9 @@ func (s *Ethereum) Start() error {
 	// Regularly update shutdown marker
 	s.shutdownTracker.Start()
 
+	// Start the database compaction scheduler
+	s.compactor.start()
+
 	// Start the networking layer
 	s.handler.Start(s.p2pServer.MaxPeers)
 	return nil
@@ -397,17 +407,32 @@ func (s *Ethereum) setupDiscovery() error {
 	return nil
 }
 
+// OwnsDatabases implements node.DatabaseOwner, the chain database is closed by
+// Stop, so the node must never abandon a stuck shutdown of the service.
+func (s *Ethereum) OwnsDatabases() bool {
+	return true
+}
+
 // Stop implements node.Lifecycle, terminating all internal goroutines used by the
 // Ethereum protocol.
+//
+// The node stops the service after the RPC endpoints and any block producers.
+// The components are torn down in dependency order: the peer-related stuff
+// feeding imports and transactions, the transaction pool, then the chain,
+// which waits for in-flight block imports and batched receipt writes to finish
+// before the database is closed.
 func (s *Ethereum) Stop() error {
 	// Stop all the peer-related stuff first.
 	s.discmix.Close()
 	s.handler.Stop()
 
 	// Then stop everything else.
+	s.compactor.stop()
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
 	s.txPool.Close()
+
+	// Wait for running chain modifications to end before closing the database
 	s.blockchain.Stop()
 	s.engine.Close()
 
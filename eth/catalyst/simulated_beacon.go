@@ -85,6 +85,7 @@ func (w *withdrawalQueue) subscribe(ch chan<- newWithdrawalsEvent) event.Subscri
 // (seconds) or on every transaction via Commit, Fork and AdjustTime.
 type SimulatedBeacon struct {
 	shutdownCh  chan struct{}
+	wg          sync.WaitGroup // Tracks the block production loop
 	eth         *eth.Ethereum
 	period      uint64
 	withdrawals withdrawalQueue
@@ -136,17 +137,26 @@ func (c *SimulatedBeacon) Start() error {
 		// this is used in the simulated backend where blocks
 		// are explicitly mined via Commit, AdjustTime and Fork
 	} else {
+		c.wg.Add(1)
 		go c.loop()
 	}
 	return nil
 }
 
-// Stop halts the SimulatedBeacon service.
+// Stop halts the SimulatedBeacon service, waiting for a block being sealed to
+// be imported.
 func (c *SimulatedBeacon) Stop() error {
 	close(c.shutdownCh)
+	c.wg.Wait()
 	return nil
 }
 
+// StopPhase implements node.StopPhaser, the simulated beacon produces blocks so
+// it's stopped before the chain it feeds.
+func (c *SimulatedBeacon) StopPhase() node.StopPhase {
+	return node.StopPhaseProducer
+}
+
 // sealBlock initiates payload building for a new block and creates a new block
 // with the completed payload.
 func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal, timestamp uint64) error {
@@ -235,6 +245,8 @@ func (c *SimulatedBeacon) sealBlock(withdrawals []*types.Withdrawal, timestamp u
 
 // loop runs the block production loop for non-zero period configuration
 func (c *SimulatedBeacon) loop() {
+	defer c.wg.Done()
+
 	timer := time.NewTimer(0)
 	for {
 		select {
@@ -95,3 +95,9 @@ func (tester *FullSyncTester) Stop() error {
 	tester.wg.Wait()
 	return nil
 }
+
+// StopPhase implements node.StopPhaser, the tester drives the chain import so
+// it's stopped before the chain it feeds.
+func (tester *FullSyncTester) StopPhase() node.StopPhase {
+	return node.StopPhaseProducer
+}
@@ -0,0 +1,280 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/crash"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// compactionIdleTime is the time which needs to pass since the last block
+	// import before the node is considered idle enough for scheduled compaction.
+	compactionIdleTime = 4 * time.Second
+
+	// compactionRecheck is the interval at which an idle period is waited for
+	// between the chunks of a scheduled compaction.
+	compactionRecheck = time.Second
+
+	// compactionRestarts is the number of times the scheduler is restarted after
+	// crashing before giving up on scheduled compaction.
+	compactionRestarts = 3
+)
+
+var (
+	// errCompactionRunning is returned if a compaction is requested while another
+	// one is still in progress.
+	errCompactionRunning = errors.New("database compaction already running")
+
+	// errCompactionAborted is returned if a compaction is interrupted, or refused,
+	// because the node is shutting down.
+	errCompactionAborted = errors.New("database compaction aborted")
+)
+
+// CompactionProgress is the progress report of a database compaction.
+type CompactionProgress struct {
+	Start     hexutil.Bytes         `json:"start"`     // First key of the compacted range
+	End       hexutil.Bytes         `json:"end"`       // Key after the compacted range (empty = end of database)
+	Current   hexutil.Bytes         `json:"current"`   // First key of the chunk being compacted
+	Done      int                   `json:"done"`      // Number of chunks already compacted
+	Total     int                   `json:"total"`     // Total number of chunks to compact
+	Scheduled bool                  `json:"scheduled"` // Whether the compaction was started by the scheduler
+	Elapsed   common.PrettyDuration `json:"elapsed"`   // Time since the compaction started
+}
+
+// compactor runs range compactions on the chain database. Apart from manual
+// compactions requested through the API, it periodically compacts the entire
+// database in small chunks, each of which is only started while the node is
+// synced and not importing blocks.
+type compactor struct {
+	db       ethdb.Database
+	chain    *core.BlockChain
+	synced   func() bool
+	interval time.Duration // Interval between scheduled full compactions, 0 = disabled
+
+	running  sync.Mutex // Ensures only one compaction runs at a time
+	lock     sync.Mutex // Protects the progress report
+	progress *CompactionProgress
+	started  time.Time
+
+	lastImport time.Time // Time of the last head import, owned by loop
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newCompactor creates a database compactor, with scheduled compaction enabled
+// if the interval is non-zero.
+func newCompactor(db ethdb.Database, chain *core.BlockChain, synced func() bool, interval time.Duration) *compactor {
+	return &compactor{
+		db:       db,
+		chain:    chain,
+		synced:   synced,
+		interval: interval,
+		quit:     make(chan struct{}),
+	}
+}
+
+// start launches the compaction scheduler if enabled.
+func (c *compactor) start() {
+	if c.interval == 0 {
+		return
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		crash.Supervise("database compaction", compactionRestarts, c.quit, nil, c.loop)
+	}()
+}
+
+// stop terminates the compaction scheduler and aborts any compaction in progress,
+// scheduled or manual, waiting for the chunk being compacted to finish.
+func (c *compactor) stop() {
+	close(c.quit)
+	c.wg.Wait()
+
+	// Manual compactions run on the API callers' goroutines, wait for them to
+	// reach the next chunk boundary, where the closed quit channel aborts them.
+	c.running.Lock()
+	c.running.Unlock()
+}
+
+// loop is the compaction scheduler, which tracks block imports and compacts
+// the database periodically during idle periods.
+func (c *compactor) loop() {
+	var (
+		heads = make(chan core.ChainHeadEvent, 16)
+		sub   = c.chain.SubscribeChainHeadEvent(heads)
+		timer = time.NewTimer(c.interval)
+	)
+	defer sub.Unsubscribe()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-heads:
+			c.lastImport = time.Now()
+
+		case <-timer.C:
+			if err := c.scheduled(heads); err != nil && !errors.Is(err, errCompactionAborted) {
+				log.Error("Scheduled database compaction failed", "err", err)
+			}
+			timer.Reset(c.interval)
+
+		case <-sub.Err():
+			return
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// scheduled runs a full compaction of the database, waiting for an idle period
+// before each chunk. It is skipped if a manual compaction is already running.
+func (c *compactor) scheduled(heads chan core.ChainHeadEvent) error {
+	if !c.running.TryLock() {
+		return nil
+	}
+	defer c.running.Unlock()
+
+	return c.compact(nil, nil, true, func() bool {
+		// Wait for an idle period before each chunk, tracking imports
+		for !c.synced() || time.Since(c.lastImport) < compactionIdleTime {
+			select {
+			case <-heads:
+				c.lastImport = time.Now()
+			case <-time.After(compactionRecheck):
+			case <-c.quit:
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// compactRange runs a manual compaction of the given key range, failing if
+// another compaction is in progress.
+func (c *compactor) compactRange(start, end []byte) error {
+	if !c.running.TryLock() {
+		return errCompactionRunning
+	}
+	defer c.running.Unlock()
+
+	return c.compact(start, end, false, nil)
+}
+
+// compact compacts the given key range chunk by chunk, splitting it along the
+// first key byte. The optional proceed callback is invoked before every chunk
+// and may abort the compaction by returning false. The compaction is aborted
+// with errCompactionAborted once the compactor is stopped.
+func (c *compactor) compact(start, end []byte, scheduled bool, proceed func() bool) error {
+	chunks := compactionChunks(start, end)
+
+	c.lock.Lock()
+	c.started = time.Now()
+	c.progress = &CompactionProgress{
+		Start:     common.CopyBytes(start),
+		End:       common.CopyBytes(end),
+		Total:     len(chunks),
+		Scheduled: scheduled,
+	}
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		c.progress = nil
+		c.lock.Unlock()
+	}()
+	for i, chunk := range chunks {
+		if proceed != nil && !proceed() {
+			return nil
+		}
+		select {
+		case <-c.quit:
+			log.Warn("Database compaction aborted", "chunk", i+1, "total", len(chunks), "elapsed", common.PrettyDuration(time.Since(c.started)))
+			return errCompactionAborted
+		default:
+		}
+		c.lock.Lock()
+		c.progress.Current = chunk[0]
+		c.progress.Done = i
+		c.lock.Unlock()
+
+		log.Info("Compacting database", "range", fmt.Sprintf("%#x-%#x", chunk[0], chunk[1]), "chunk", i+1, "total", len(chunks), "elapsed", common.PrettyDuration(time.Since(c.started)))
+		if err := c.db.Compact(chunk[0], chunk[1]); err != nil {
+			return err
+		}
+	}
+	log.Info("Database compaction finished", "chunks", len(chunks), "elapsed", common.PrettyDuration(time.Since(c.started)))
+	return nil
+}
+
+// status returns the progress of the running compaction, or nil if there is
+// none in progress.
+func (c *compactor) status() *CompactionProgress {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.progress == nil {
+		return nil
+	}
+	progress := *c.progress
+	progress.Elapsed = common.PrettyDuration(time.Since(c.started))
+	return &progress
+}
+
+// compactionChunks splits the key range [start, end) into chunks along the
+// first key byte. A nil start denotes the beginning of the key space, a nil end
+// its end.
+func compactionChunks(start, end []byte) [][2][]byte {
+	var chunks [][2][]byte
+	for b := 0; b <= 255; b++ {
+		var (
+			from = []byte{byte(b)}
+			to   = []byte{byte(b + 1)}
+		)
+		if b == 255 {
+			to = nil
+		}
+		// Skip chunks entirely outside of the requested range
+		if start != nil && to != nil && bytes.Compare(to, start) <= 0 {
+			continue
+		}
+		if end != nil && bytes.Compare(from, end) >= 0 {
+			break
+		}
+		// Clamp the chunk to the requested range
+		if start != nil && bytes.Compare(from, start) < 0 {
+			from = start
+		}
+		if end != nil && (to == nil || bytes.Compare(to, end) > 0) {
+			to = end
+		}
+		chunks = append(chunks, [2][]byte{from, to})
+	}
+	return chunks
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+func TestCompactionChunks(t *testing.T) {
+	tests := []struct {
+		start, end []byte
+		chunks     int
+		first      [2][]byte
+		last       [2][]byte
+	}{
+		{nil, nil, 256, [2][]byte{{0x00}, {0x01}}, [2][]byte{{0xff}, nil}},
+		{[]byte{0x10}, nil, 240, [2][]byte{{0x10}, {0x11}}, [2][]byte{{0xff}, nil}},
+		{nil, []byte{0x10}, 16, [2][]byte{{0x00}, {0x01}}, [2][]byte{{0x0f}, {0x10}}},
+		{[]byte{0x10, 0x80}, []byte{0x12, 0x01}, 3, [2][]byte{{0x10, 0x80}, {0x11}}, [2][]byte{{0x12}, {0x12, 0x01}}},
+		{[]byte{0x10, 0x01}, []byte{0x10, 0x02}, 1, [2][]byte{{0x10, 0x01}, {0x10, 0x02}}, [2][]byte{{0x10, 0x01}, {0x10, 0x02}}},
+		{[]byte{0xff, 0x01}, nil, 1, [2][]byte{{0xff, 0x01}, nil}, [2][]byte{{0xff, 0x01}, nil}},
+	}
+	for i, tt := range tests {
+		chunks := compactionChunks(tt.start, tt.end)
+		if len(chunks) != tt.chunks {
+			t.Errorf("test %d: chunk count mismatch: have %d, want %d", i, len(chunks), tt.chunks)
+			continue
+		}
+		first, last := chunks[0], chunks[len(chunks)-1]
+		if !bytes.Equal(first[0], tt.first[0]) || !bytes.Equal(first[1], tt.first[1]) {
+			t.Errorf("test %d: first chunk mismatch: have %x, want %x", i, first, tt.first)
+		}
+		if !bytes.Equal(last[0], tt.last[0]) || !bytes.Equal(last[1], tt.last[1]) {
+			t.Errorf("test %d: last chunk mismatch: have %x, want %x", i, last, tt.last)
+		}
+	}
+}
+
+func TestCompactRange(t *testing.T) {
+	c := newCompactor(rawdb.NewMemoryDatabase(), nil, func() bool { return true }, 0)
+
+	if err := c.compactRange([]byte{0x01}, []byte{0x03}); err != nil {
+		t.Fatalf("failed to compact range: %v", err)
+	}
+	if progress := c.status(); progress != nil {
+		t.Fatalf("progress reported after compaction: %+v", progress)
+	}
+	// Concurrent compactions must be rejected
+	c.running.Lock()
+	if err := c.compactRange(nil, nil); !errors.Is(err, errCompactionRunning) {
+		t.Fatalf("concurrent compaction error mismatch: have %v, want %v", err, errCompactionRunning)
+	}
+	c.running.Unlock()
+
+	// Progress must be reported while a compaction is running
+	var seen int
+	err := c.compact([]byte{0x01}, []byte{0x03}, true, func() bool {
+		progress := c.status()
+		if progress == nil || progress.Total != 2 || !progress.Scheduled {
+			t.Fatalf("unexpected progress: %+v", progress)
+		}
+		seen++
+		return seen < 2 // Abort before the second chunk
+	})
+	if err != nil {
+		t.Fatalf("failed to compact range: %v", err)
+	}
+	if seen != 2 {
+		t.Fatalf("proceed callback count mismatch: have %d, want 2", seen)
+	}
+}
+
+// blockingCompactDB is a database whose compactions block until released.
+type blockingCompactDB struct {
+	ethdb.Database
+	started chan struct{}
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (db *blockingCompactDB) Compact(start, limit []byte) error {
+	db.calls.Add(1)
+	db.started <- struct{}{}
+	<-db.release
+	return nil
+}
+
+// Tests that stopping the compactor waits for the chunk of a manual compaction
+// being compacted, aborting the rest of it, and refuses new compactions.
+func TestCompactorStopAbortsManual(t *testing.T) {
+	db := &blockingCompactDB{
+		Database: rawdb.NewMemoryDatabase(),
+		started:  make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+	c := newCompactor(db, nil, func() bool { return true }, 0)
+
+	result := make(chan error, 1)
+	go func() { result <- c.compactRange(nil, nil) }()
+	<-db.started
+
+	stopped := make(chan struct{})
+	go func() {
+		c.stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		t.Fatal("compactor stopped while a chunk was being compacted")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(db.release)
+
+	if err := <-result; !errors.Is(err, errCompactionAborted) {
+		t.Fatalf("compaction error mismatch: have %v, want %v", err, errCompactionAborted)
+	}
+	<-stopped
+	if calls := db.calls.Load(); calls != 1 {
+		t.Fatalf("compacted chunk count mismatch: have %d, want 1", calls)
+	}
+	if err := c.compactRange(nil, nil); !errors.Is(err, errCompactionAborted) {
+		t.Fatalf("compaction after stop error mismatch: have %v, want %v", err, errCompactionAborted)
+	}
+}
@@ -19,6 +19,9 @@ package ethconfig
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -112,6 +115,11 @@ type Config struct {
 	DatabaseCache      int
 	DatabaseFreezer    string
 
+	// DatabaseCompactionInterval is the interval between scheduled compactions
+	// of the chain database, run chunk by chunk while the node is idle. Zero
+	// disables the scheduler.
+	DatabaseCompactionInterval time.Duration
+
 	TrieCleanCache int
 	TrieDirtyCache int
 	TrieTimeout    time.Duration
@@ -147,6 +155,10 @@ type Config struct {
 	// RPCEVMTimeout is the global timeout for eth-call.
 	RPCEVMTimeout time.Duration
 
+	// RPCMethodLimits overrides the global gas cap and EVM timeout for individual
+	// simulation methods, keyed by method name (see RPCLimitedMethods).
+	RPCMethodLimits map[string]RPCLimits `toml:",omitempty"`
+
 	// RPCTxFeeCap is the global transaction fee(price * gaslimit) cap for
 	// send-transaction variants. The unit is ether.
 	RPCTxFeeCap float64
@@ -173,3 +185,46 @@ func CreateConsensusEngine(config *params.ChainConfig, db ethdb.Database) (conse
 	}
 	return beacon.New(ethash.NewFaker()), nil
 }
+
+// RPCLimitedMethods are the RPC simulation methods whose execution limits can be
+// overridden through RPCMethodLimits.
+var RPCLimitedMethods = []string{"eth_call", "eth_estimateGas", "eth_simulateV1", "debug_traceCall"}
+
+// RPCLimits are the execution limits of a single RPC simulation method. Unset
+// fields fall back to the global RPCGasCap and RPCEVMTimeout, whereas zero lifts
+// the limit for the method, same as it does for the global settings. The limits
+// apply to the GraphQL call and estimateGas fields as well.
+//
+// There is no separate memory cap: the EVM memory of a simulation is bounded by
+// the gas cap through the quadratic cost of memory expansion.
+type RPCLimits struct {
+	GasCap     *uint64        `toml:",omitempty"`
+	EVMTimeout *time.Duration `toml:",omitempty"`
+}
+
+// MethodLimits returns the gas cap and EVM timeout of an RPC simulation method,
+// taking any per-method override into account. Zero means unlimited.
+func (c *Config) MethodLimits(method string) (gasCap uint64, timeout time.Duration) {
+	gasCap, timeout = c.RPCGasCap, c.RPCEVMTimeout
+	if limits, ok := c.RPCMethodLimits[method]; ok {
+		if limits.GasCap != nil {
+			gasCap = *limits.GasCap
+		}
+		if limits.EVMTimeout != nil {
+			timeout = *limits.EVMTimeout
+		}
+	}
+	return gasCap, timeout
+}
+
+// ValidateRPCMethodLimits ensures the per-method limit overrides only reference
+// known simulation methods, catching typos which would otherwise be silently
+// ignored.
+func (c *Config) ValidateRPCMethodLimits() error {
+	for method := range c.RPCMethodLimits {
+		if !slices.Contains(RPCLimitedMethods, method) {
+			return fmt.Errorf("invalid RPC method limits for %q, supported methods: %s", method, strings.Join(RPCLimitedMethods, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethconfig
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMethodLimits(t *testing.T) {
+	var (
+		gasCap  = uint64(1000)
+		timeout = 2 * time.Second
+		zero    = uint64(0)
+		never   = time.Duration(0)
+	)
+	config := &Config{
+		RPCGasCap:     50_000_000,
+		RPCEVMTimeout: 5 * time.Second,
+		RPCMethodLimits: map[string]RPCLimits{
+			"eth_call":        {GasCap: &gasCap},
+			"eth_estimateGas": {EVMTimeout: &timeout},
+			"eth_simulateV1":  {GasCap: &zero, EVMTimeout: &never},
+		},
+	}
+	tests := []struct {
+		method  string
+		gasCap  uint64
+		timeout time.Duration
+	}{
+		// Partial overrides fall back to the global limits for unset fields
+		{"eth_call", 1000, 5 * time.Second},
+		{"eth_estimateGas", 50_000_000, 2 * time.Second},
+		// Explicit zeroes lift the limits for the method
+		{"eth_simulateV1", 0, 0},
+		// Methods without overrides use the global limits
+		{"debug_traceCall", 50_000_000, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		gasCap, timeout := config.MethodLimits(tt.method)
+		if gasCap != tt.gasCap || timeout != tt.timeout {
+			t.Errorf("%s: limits mismatch: have (%d, %v), want (%d, %v)", tt.method, gasCap, timeout, tt.gasCap, tt.timeout)
+		}
+	}
+}
+
+func TestValidateRPCMethodLimits(t *testing.T) {
+	valid := &Config{RPCMethodLimits: make(map[string]RPCLimits)}
+	for _, method := range RPCLimitedMethods {
+		valid.RPCMethodLimits[method] = RPCLimits{}
+	}
+	if err := valid.ValidateRPCMethodLimits(); err != nil {
+		t.Fatalf("valid limits rejected: %v", err)
+	}
+	invalid := &Config{RPCMethodLimits: map[string]RPCLimits{"eth_estimategas": {}}}
+	if err := invalid.ValidateRPCMethodLimits(); err == nil {
+		t.Fatal("unknown method accepted")
+	}
+}
@@ -224,6 +224,11 @@ func (api *FilterAPI) NewBlockFilter() rpc.ID {
 }
 
 // NewHeads send a notification each time a new (header) block is appended to the chain.
+//
+// Chain events are relayed to the event system through a bounded buffer, which
+// drops the oldest events if the system falls behind (counted by the
+// event/buffered/filters/chain/dropped meter). Subscribers may thus see gaps in
+// the notified headers and should backfill them by block number.
 func (api *FilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
@@ -251,6 +256,11 @@ func (api *FilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 }
 
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
+//
+// Logs removed by reorgs are relayed to the event system through a bounded
+// buffer, which drops the oldest events if the system falls behind (counted by
+// the event/buffered/filters/rmlogs/dropped meter). Removal notifications may
+// thus be missing, so subscribers should check the canonical chain on reorgs.
 func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
@@ -221,16 +221,19 @@ func NewEventSystem(sys *FilterSystem) *EventSystem {
 		chainCh:   make(chan core.ChainEvent, chainEvChanSize),
 	}
 
-	// Subscribe events
-	m.txsSub = m.backend.SubscribeNewTxsEvent(m.txsCh)
-	m.logsSub = m.backend.SubscribeLogsEvent(m.logsCh)
-	m.rmLogsSub = m.backend.SubscribeRemovedLogsEvent(m.rmLogsCh)
-	m.chainSub = m.backend.SubscribeChainEvent(m.chainCh)
-
+	// Subscribe events. The transaction, reorg and chain feeds are relayed through
+	// bounded buffers, so a stalled event loop never blocks the pool or the chain.
+	var (
+		txsSub    = event.SubscribeBuffered("filters/txs", m.backend.SubscribeNewTxsEvent, m.txsCh, txChanSize)
+		logsSub   = m.backend.SubscribeLogsEvent(m.logsCh)
+		rmLogsSub = event.SubscribeBuffered("filters/rmlogs", m.backend.SubscribeRemovedLogsEvent, m.rmLogsCh, rmLogsChanSize)
+		chainSub  = event.SubscribeBuffered("filters/chain", m.backend.SubscribeChainEvent, m.chainCh, chainEvChanSize)
+	)
 	// Make sure none of the subscriptions are empty
-	if m.txsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil {
+	if txsSub == nil || logsSub == nil || rmLogsSub == nil || chainSub == nil {
 		log.Crit("Subscribe for event system failed")
 	}
+	m.txsSub, m.logsSub, m.rmLogsSub, m.chainSub = txsSub, logsSub, rmLogsSub, chainSub
 
 	go m.eventLoop()
 	return m
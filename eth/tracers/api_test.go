@@ -119,8 +119,8 @@ func (b *testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (b
 	return tx != nil, tx, hash, blockNumber, index, nil
 }
 
-func (b *testBackend) RPCGasCap() uint64 {
-	return 25000000
+func (b *testBackend) RPCMethodLimits(method string) (uint64, time.Duration) {
+	return 25000000, 0
 }
 
 func (b *testBackend) ChainConfig() *params.ChainConfig {
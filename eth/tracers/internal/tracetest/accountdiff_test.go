@@ -0,0 +1,253 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/live"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestAccountDiff(t *testing.T) {
+	var (
+		config = *params.AllEthashProtocolChanges
+
+		aa = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+		bb = common.HexToAddress("0x000000000000000000000000000000000000bbbb")
+		// A sender who makes transactions, has some eth1
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		gwei5   = new(big.Int).Mul(big.NewInt(5), big.NewInt(params.GWei))
+		eth1    = new(big.Int).Mul(common.Big1, big.NewInt(params.Ether))
+
+		gspec = &core.Genesis{
+			Config:  &config,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Alloc: types.GenesisAlloc{
+				addr1: {Balance: eth1},
+				// PUSH1 0x0, PUSH1 0x0, REVERT
+				bb: {Code: []byte{byte(vm.PUSH1), 0x0, byte(vm.PUSH1), 0x0, byte(vm.REVERT)}},
+			},
+		}
+	)
+	signer := types.LatestSigner(gspec.Config)
+
+	gen := func(b *core.BlockGen) {
+		// A plain value transfer and a value transfer reverted by the callee
+		for i, to := range []common.Address{aa, bb} {
+			tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+				ChainID:   gspec.Config.ChainID,
+				Nonce:     uint64(i),
+				To:        &to,
+				Value:     big.NewInt(1000),
+				Gas:       50000,
+				GasFeeCap: gwei5,
+				GasTipCap: big.NewInt(2),
+			}), signer, key1)
+			b.AddTx(tx)
+		}
+	}
+	changes := make(chan *live.AccountChanges, 1)
+	sub := live.SubscribeAccountChanges(changes)
+	defer sub.Unsubscribe()
+
+	out, chain, err := testAccountDiffTracer(t, gspec, gen)
+	if err != nil {
+		t.Fatalf("failed to test account diff tracer: %v", err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("unexpected number of blocks: have %d, want 1", len(out))
+	}
+	var (
+		head      = chain.CurrentBlock()
+		parent, _ = chain.StateAt(chain.GetHeaderByNumber(0).Root)
+		state, _  = chain.StateAt(head.Root)
+		coinbase  = common.Address{1}
+	)
+	if out[0].Hash != head.Hash() || out[0].ParentHash != head.ParentHash || out[0].Number != 1 {
+		t.Fatalf("block mismatch: have %d %x, want %d %x", out[0].Number, out[0].Hash, head.Number, head.Hash())
+	}
+	// The reverted transfer must not show up, everything else must match the state
+	want := []common.Address{aa, coinbase, addr1}
+	if len(out[0].Accounts) != len(want) {
+		t.Fatalf("unexpected number of changed accounts: have %d, want %d", len(out[0].Accounts), len(want))
+	}
+	for i, change := range out[0].Accounts {
+		if change.Address != want[i] {
+			t.Errorf("account %d: address mismatch: have %x, want %x", i, change.Address, want[i])
+			continue
+		}
+		var (
+			from = parent.GetBalance(change.Address).ToBig()
+			to   = state.GetBalance(change.Address).ToBig()
+		)
+		if change.BalanceFrom.ToInt().Cmp(from) != 0 || change.BalanceTo.ToInt().Cmp(to) != 0 {
+			t.Errorf("account %x: balance mismatch: have %v -> %v, want %v -> %v", change.Address, change.BalanceFrom, change.BalanceTo, from, to)
+		}
+		if change.Address == addr1 {
+			if change.NonceFrom == nil || *change.NonceFrom != 0 || change.NonceTo == nil || *change.NonceTo != 2 {
+				t.Errorf("sender nonce mismatch: have %v -> %v, want 0 -> 2", change.NonceFrom, change.NonceTo)
+			}
+		} else if change.NonceFrom != nil {
+			t.Errorf("account %x: unexpected nonce change", change.Address)
+		}
+	}
+	// Subscribers must receive the same changes as the index
+	select {
+	case ev := <-changes:
+		compareAsJSON(t, out[0], ev)
+	default:
+		t.Fatal("no account changes delivered to subscriber")
+	}
+}
+
+func TestAccountDiffCodeChanges(t *testing.T) {
+	var (
+		config = *params.AllEthashProtocolChanges
+
+		cc = common.HexToAddress("0x000000000000000000000000000000000000cccc")
+		// A sender who makes transactions, has some eth1
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		gwei5   = new(big.Int).Mul(big.NewInt(5), big.NewInt(params.GWei))
+		eth1    = new(big.Int).Mul(common.Big1, big.NewInt(params.Ether))
+
+		gspec = &core.Genesis{
+			Config:  &config,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Alloc: types.GenesisAlloc{
+				addr1: {Balance: eth1},
+				// PUSH1 0x0, SELFDESTRUCT
+				cc: {Code: []byte{byte(vm.PUSH1), 0x0, byte(vm.SELFDESTRUCT)}, Balance: big.NewInt(1)},
+			},
+		}
+		created = crypto.CreateAddress(addr1, 0)
+	)
+	signer := types.LatestSigner(gspec.Config)
+
+	gen := func(b *core.BlockGen) {
+		// Deploy a contract, then destroy a pre-existing one (pre-Cancun)
+		txs := []*types.DynamicFeeTx{
+			// PUSH1 0x1, PUSH1 0x0, RETURN: deploys a single zero byte
+			{Data: []byte{byte(vm.PUSH1), 0x1, byte(vm.PUSH1), 0x0, byte(vm.RETURN)}},
+			{To: &cc},
+		}
+		for i, inner := range txs {
+			inner.ChainID = gspec.Config.ChainID
+			inner.Nonce = uint64(i)
+			inner.Gas = 100000
+			inner.GasFeeCap = gwei5
+			inner.GasTipCap = big.NewInt(2)
+
+			tx, _ := types.SignTx(types.NewTx(inner), signer, key1)
+			b.AddTx(tx)
+		}
+	}
+	out, chain, err := testAccountDiffTracer(t, gspec, gen)
+	if err != nil {
+		t.Fatalf("failed to test account diff tracer: %v", err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("unexpected number of blocks: have %d, want 1", len(out))
+	}
+	state, _ := chain.StateAt(chain.CurrentBlock().Root)
+	if len(state.GetCode(created)) == 0 || state.Exist(cc) {
+		t.Fatal("contract not deployed or not destroyed")
+	}
+	tests := []struct {
+		addr      common.Address
+		created   bool
+		destroyed bool
+	}{
+		{created, true, false},
+		{cc, false, true},
+		{addr1, false, false},
+	}
+	for _, tt := range tests {
+		var change *live.AccountChange
+		for i := range out[0].Accounts {
+			if out[0].Accounts[i].Address == tt.addr {
+				change = &out[0].Accounts[i]
+			}
+		}
+		if change == nil {
+			t.Errorf("account %x: no change reported", tt.addr)
+			continue
+		}
+		if change.Created != tt.created || change.Destroyed != tt.destroyed {
+			t.Errorf("account %x: code change mismatch: have created %v destroyed %v, want created %v destroyed %v",
+				tt.addr, change.Created, change.Destroyed, tt.created, tt.destroyed)
+		}
+	}
+}
+
+func testAccountDiffTracer(t *testing.T, genesis *core.Genesis, gen func(*core.BlockGen)) ([]live.AccountChanges, *core.BlockChain, error) {
+	engine := beacon.New(ethash.NewFaker())
+
+	traceOutputPath := filepath.ToSlash(t.TempDir())
+	tracer, err := tracers.LiveDirectory.New("accountdiff", json.RawMessage(fmt.Sprintf(`{"path":"%s"}`, traceOutputPath)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create account diff tracer: %v", err)
+	}
+	if !live.AccountDiffEnabled() {
+		tracer.OnClose()
+		return nil, nil, fmt.Errorf("account diff tracer not reported as enabled")
+	}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), core.DefaultCacheConfigWithScheme(rawdb.PathScheme), genesis, nil, engine, vm.Config{Tracer: tracer}, nil)
+	if err != nil {
+		tracer.OnClose()
+		return nil, nil, fmt.Errorf("failed to create tester chain: %v", err)
+	}
+	t.Cleanup(chain.Stop) // Closes the tracer too
+
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, engine, 1, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{1})
+		gen(b)
+	})
+	if n, err := chain.InsertChain(blocks); err != nil {
+		return nil, chain, fmt.Errorf("block %d: failed to insert into chain: %v", n, err)
+	}
+	var output []live.AccountChanges
+	for _, block := range blocks {
+		changes, err := live.ReadAccountChanges(block.NumberU64(), block.Hash())
+		if err != nil {
+			return nil, chain, fmt.Errorf("failed to read account changes of block %d: %v", block.NumberU64(), err)
+		}
+		if changes == nil {
+			return nil, chain, fmt.Errorf("block %d not indexed", block.NumberU64())
+		}
+		output = append(output, *changes)
+	}
+	if changes, err := live.ReadAccountChanges(0, chain.Genesis().Hash()); changes != nil || err != nil {
+		return nil, chain, fmt.Errorf("unexpected genesis account changes: %v, %v", changes, err)
+	}
+	return output, chain, nil
+}
@@ -0,0 +1,396 @@
+package live
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func init() {
+	tracers.LiveDirectory.Register("accountdiff", newAccountDiff)
+}
+
+var (
+	// accountChangesFeed publishes the account changes of every processed block.
+	accountChangesFeed event.Feed
+
+	// accountDiffActive counts the running account diff tracers.
+	accountDiffActive atomic.Int32
+
+	// accountDiffIndex is the per-block change index of the running account
+	// diff tracer, nil if none is running with the index enabled.
+	accountDiffIndex atomic.Pointer[leveldb.Database]
+
+	// errAccountDiffIndexDisabled is returned when looking up the changes of a
+	// block without the account diff index enabled.
+	errAccountDiffIndexDisabled = errors.New("account diff index not enabled")
+)
+
+// Bit flags of the fields present in a stored account change.
+const (
+	accountChangeBalance = 1 << iota
+	accountChangeNonce
+	accountChangeCreated
+	accountChangeDestroyed
+)
+
+// AccountChange is the net change of a single account within a block.
+type AccountChange struct {
+	Address     common.Address  `json:"address"`
+	BalanceFrom *hexutil.Big    `json:"balanceFrom,omitempty"`
+	BalanceTo   *hexutil.Big    `json:"balanceTo,omitempty"`
+	NonceFrom   *hexutil.Uint64 `json:"nonceFrom,omitempty"`
+	NonceTo     *hexutil.Uint64 `json:"nonceTo,omitempty"`
+	Created     bool            `json:"created,omitempty"`   // Contract code deployed
+	Destroyed   bool            `json:"destroyed,omitempty"` // Contract code removed
+}
+
+// AccountChanges is the list of account changes of a block. Changes are emitted
+// for every processed block, including blocks which don't end up in the
+// canonical chain, so consumers should key them by block hash.
+type AccountChanges struct {
+	Number     uint64          `json:"blockNumber"`
+	Hash       common.Hash     `json:"hash"`
+	ParentHash common.Hash     `json:"parentHash"`
+	Accounts   []AccountChange `json:"accounts"`
+}
+
+// storedAccountChange is the compact index encoding of an AccountChange. The
+// flags mark which of the optional fields are set, the unset ones are zero.
+type storedAccountChange struct {
+	Address     common.Address
+	Flags       uint8
+	BalanceFrom *big.Int
+	BalanceTo   *big.Int
+	NonceFrom   uint64
+	NonceTo     uint64
+}
+
+// storedAccountChanges is the compact index encoding of AccountChanges, keyed
+// by the block number and hash.
+type storedAccountChanges struct {
+	ParentHash common.Hash
+	Accounts   []storedAccountChange
+}
+
+// accountDiffKey = number (uint64 big endian) + hash
+func accountDiffKey(number uint64, hash common.Hash) []byte {
+	key := binary.BigEndian.AppendUint64(make([]byte, 0, 8+common.HashLength), number)
+	return append(key, hash.Bytes()...)
+}
+
+// AccountDiffEnabled reports whether the account diff tracer is running.
+func AccountDiffEnabled() bool {
+	return accountDiffActive.Load() > 0
+}
+
+// SubscribeAccountChanges registers a subscription for the account changes of
+// processed blocks. Events are only sent while the account diff tracer is
+// running, see AccountDiffEnabled. Block processing waits for subscribers to
+// receive the events, so slow consumers should subscribe through a buffer.
+func SubscribeAccountChanges(ch chan<- *AccountChanges) event.Subscription {
+	return accountChangesFeed.Subscribe(ch)
+}
+
+// accountState tracks an account touched within the current block.
+type accountState struct {
+	prevBalance, balance *big.Int
+	prevNonce, nonce     uint64
+	nonceSet             bool
+
+	prevCode, code bool // Whether the account had code before and has code now
+	codeSet        bool // Whether prevCode is known, i.e. the account was touched by a transaction
+}
+
+// accountDiff is a live tracer which collects the net balance, nonce and code
+// changes of the accounts touched by every block. The changes are published to
+// subscribers and optionally stored in a per-block index, keyed by the block
+// number and hash, see ReadAccountChanges.
+//
+// Balance and nonce hooks are not emitted when state changes are reverted, and
+// no code hook is emitted when a contract is destructed, so the values of the
+// accounts touched by a transaction are re-read from the state once it's done.
+type accountDiff struct {
+	block    *AccountChanges
+	accounts map[common.Address]*accountState
+
+	state   tracing.StateDB             // State of the running transaction
+	touched map[common.Address]struct{} // Accounts touched by the running transaction
+
+	index *leveldb.Database // Optional per-block change index, nil if disabled
+}
+
+type accountDiffTracerConfig struct {
+	Path string `json:"path"` // Path to the directory where the change index will be stored, empty = no index
+}
+
+func newAccountDiff(cfg json.RawMessage) (*tracing.Hooks, error) {
+	var config accountDiffTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse config: %v", err)
+		}
+	}
+	t := &accountDiff{
+		accounts: make(map[common.Address]*accountState),
+		touched:  make(map[common.Address]struct{}),
+	}
+	if config.Path != "" {
+		db, err := leveldb.New(filepath.Join(config.Path, "accountdiff"), 16, 16, "eth/tracers/live/accountdiff/", false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open account diff index: %v", err)
+		}
+		if !accountDiffIndex.CompareAndSwap(nil, db) {
+			db.Close()
+			return nil, errors.New("account diff index already open")
+		}
+		t.index = db
+	}
+	accountDiffActive.Add(1)
+
+	return &tracing.Hooks{
+		OnBlockStart:    t.OnBlockStart,
+		OnBlockEnd:      t.OnBlockEnd,
+		OnTxStart:       t.OnTxStart,
+		OnTxEnd:         t.OnTxEnd,
+		OnBalanceChange: t.OnBalanceChange,
+		OnNonceChange:   t.OnNonceChange,
+		OnCodeChange:    t.OnCodeChange,
+		OnClose:         t.OnClose,
+	}, nil
+}
+
+func (t *accountDiff) OnBlockStart(ev tracing.BlockEvent) {
+	t.block = &AccountChanges{
+		Number:     ev.Block.NumberU64(),
+		Hash:       ev.Block.Hash(),
+		ParentHash: ev.Block.ParentHash(),
+	}
+	clear(t.accounts)
+}
+
+func (t *accountDiff) OnTxStart(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	t.state = vm.StateDB
+	clear(t.touched)
+}
+
+func (t *accountDiff) OnTxEnd(receipt *types.Receipt, err error) {
+	// Refresh the accounts touched by the transaction, as any reverted changes
+	// were not reported through the hooks
+	if t.state != nil {
+		for addr := range t.touched {
+			acc := t.accounts[addr]
+			acc.balance = t.state.GetBalance(addr).ToBig()
+			if acc.nonceSet {
+				acc.nonce = t.state.GetNonce(addr)
+			}
+			acc.code = hasCode(t.state, addr)
+		}
+	}
+	t.state = nil
+	clear(t.touched)
+}
+
+// account returns the tracked state of an account, starting to track it if not
+// seen yet in the block. Hooks fire before the state is modified, so the code
+// of an account first touched by a transaction is its code before the block.
+func (t *accountDiff) account(addr common.Address) *accountState {
+	acc, ok := t.accounts[addr]
+	if !ok {
+		acc = new(accountState)
+		t.accounts[addr] = acc
+	}
+	if t.state != nil {
+		t.touched[addr] = struct{}{}
+		if !acc.codeSet {
+			acc.prevCode, acc.codeSet = hasCode(t.state, addr), true
+			acc.code = acc.prevCode
+		}
+	}
+	return acc
+}
+
+// hasCode reports whether the account at addr has contract code in the given
+// state, without loading the code itself.
+func hasCode(state tracing.StateDB, addr common.Address) bool {
+	hash := state.GetCodeHash(addr)
+	return hash != (common.Hash{}) && hash != types.EmptyCodeHash
+}
+
+func (t *accountDiff) OnBalanceChange(addr common.Address, prev, cur *big.Int, reason tracing.BalanceChangeReason) {
+	if t.block == nil {
+		return // Genesis or state changes outside of block processing
+	}
+	acc := t.account(addr)
+	if acc.prevBalance == nil {
+		acc.prevBalance = new(big.Int).Set(prev)
+	}
+	acc.balance = new(big.Int).Set(cur)
+}
+
+func (t *accountDiff) OnNonceChange(addr common.Address, prev, cur uint64) {
+	if t.block == nil {
+		return
+	}
+	acc := t.account(addr)
+	if !acc.nonceSet {
+		acc.prevNonce, acc.nonceSet = prev, true
+	}
+	acc.nonce = cur
+}
+
+func (t *accountDiff) OnCodeChange(addr common.Address, prevCodeHash common.Hash, prevCode []byte, codeHash common.Hash, code []byte) {
+	if t.block == nil {
+		return
+	}
+	acc := t.account(addr)
+	if !acc.codeSet {
+		acc.prevCode, acc.codeSet = prevCodeHash != (common.Hash{}) && prevCodeHash != types.EmptyCodeHash, true
+	}
+	acc.code = len(code) > 0 && codeHash != types.EmptyCodeHash
+}
+
+func (t *accountDiff) OnBlockEnd(err error) {
+	defer func() { t.block = nil }()
+
+	if err != nil || t.block == nil {
+		return // Invalid blocks don't change any state
+	}
+	changes := t.block
+	changes.Accounts = make([]AccountChange, 0, len(t.accounts))
+	for addr, acc := range t.accounts {
+		change := AccountChange{Address: addr}
+		if acc.prevBalance != nil && acc.balance.Cmp(acc.prevBalance) != 0 {
+			change.BalanceFrom, change.BalanceTo = (*hexutil.Big)(acc.prevBalance), (*hexutil.Big)(acc.balance)
+		}
+		if acc.nonceSet && acc.nonce != acc.prevNonce {
+			from, to := hexutil.Uint64(acc.prevNonce), hexutil.Uint64(acc.nonce)
+			change.NonceFrom, change.NonceTo = &from, &to
+		}
+		if acc.codeSet {
+			change.Created = !acc.prevCode && acc.code
+			change.Destroyed = acc.prevCode && !acc.code
+		}
+		if change.BalanceFrom == nil && change.NonceFrom == nil && !change.Created && !change.Destroyed {
+			continue // Reverted or net zero change
+		}
+		changes.Accounts = append(changes.Accounts, change)
+	}
+	slices.SortFunc(changes.Accounts, func(a, b AccountChange) int {
+		return a.Address.Cmp(b.Address)
+	})
+	accountChangesFeed.Send(changes)
+	t.write(changes)
+}
+
+func (t *accountDiff) OnClose() {
+	accountDiffActive.Add(-1)
+	if t.index == nil {
+		return
+	}
+	accountDiffIndex.CompareAndSwap(t.index, nil)
+	if err := t.index.Close(); err != nil {
+		log.Warn("failed to close account diff index", "error", err)
+	}
+}
+
+// write stores the changes of a block in the index, if enabled.
+func (t *accountDiff) write(changes *AccountChanges) {
+	if t.index == nil {
+		return
+	}
+	stored := storedAccountChanges{
+		ParentHash: changes.ParentHash,
+		Accounts:   make([]storedAccountChange, len(changes.Accounts)),
+	}
+	for i, change := range changes.Accounts {
+		enc := storedAccountChange{
+			Address:     change.Address,
+			BalanceFrom: new(big.Int),
+			BalanceTo:   new(big.Int),
+		}
+		if change.BalanceFrom != nil {
+			enc.Flags |= accountChangeBalance
+			enc.BalanceFrom, enc.BalanceTo = change.BalanceFrom.ToInt(), change.BalanceTo.ToInt()
+		}
+		if change.NonceFrom != nil {
+			enc.Flags |= accountChangeNonce
+			enc.NonceFrom, enc.NonceTo = uint64(*change.NonceFrom), uint64(*change.NonceTo)
+		}
+		if change.Created {
+			enc.Flags |= accountChangeCreated
+		}
+		if change.Destroyed {
+			enc.Flags |= accountChangeDestroyed
+		}
+		stored.Accounts[i] = enc
+	}
+	blob, err := rlp.EncodeToBytes(&stored)
+	if err != nil {
+		log.Warn("failed to encode account changes", "number", changes.Number, "hash", changes.Hash, "error", err)
+		return
+	}
+	if err := t.index.Put(accountDiffKey(changes.Number, changes.Hash), blob); err != nil {
+		log.Warn("failed to write account changes", "number", changes.Number, "hash", changes.Hash, "error", err)
+	}
+}
+
+// ReadAccountChanges retrieves the account changes of the block with the given
+// number and hash from the index of the running account diff tracer. It returns
+// nil if the block was not indexed, i.e. it wasn't processed while the tracer
+// was running.
+func ReadAccountChanges(number uint64, hash common.Hash) (*AccountChanges, error) {
+	db := accountDiffIndex.Load()
+	if db == nil {
+		return nil, errAccountDiffIndexDisabled
+	}
+	key := accountDiffKey(number, hash)
+	if ok, err := db.Has(key); !ok || err != nil {
+		return nil, err
+	}
+	blob, err := db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	var stored storedAccountChanges
+	if err := rlp.DecodeBytes(blob, &stored); err != nil {
+		return nil, fmt.Errorf("invalid account changes of block %d (%x): %v", number, hash, err)
+	}
+	changes := &AccountChanges{
+		Number:     number,
+		Hash:       hash,
+		ParentHash: stored.ParentHash,
+		Accounts:   make([]AccountChange, len(stored.Accounts)),
+	}
+	for i, enc := range stored.Accounts {
+		change := AccountChange{
+			Address:   enc.Address,
+			Created:   enc.Flags&accountChangeCreated != 0,
+			Destroyed: enc.Flags&accountChangeDestroyed != 0,
+		}
+		if enc.Flags&accountChangeBalance != 0 {
+			change.BalanceFrom, change.BalanceTo = (*hexutil.Big)(enc.BalanceFrom), (*hexutil.Big)(enc.BalanceTo)
+		}
+		if enc.Flags&accountChangeNonce != 0 {
+			from, to := hexutil.Uint64(enc.NonceFrom), hexutil.Uint64(enc.NonceTo)
+			change.NonceFrom, change.NonceTo = &from, &to
+		}
+		changes.Accounts[i] = change
+	}
+	return changes, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package event
+
+import (
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// BufferedSubscription is a subscription which decouples the sender of events
+// from a potentially slow consumer through a bounded buffer. If the consumer
+// falls behind and the buffer fills up, the oldest events are dropped instead
+// of blocking the sender.
+type BufferedSubscription struct {
+	Subscription
+	dropped atomic.Uint64
+}
+
+// Dropped returns the number of events dropped because the consumer was too
+// slow to keep up. A non-zero value identifies a slow subscriber.
+func (s *BufferedSubscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// SubscribeBuffered subscribes to an event source (e.g. a FeedOf or one of the
+// typed Subscribe methods of the blockchain) and relays its events into channel
+// through a buffer holding at most size events. The source is always drained
+// by a dedicated goroutine, so a stalled consumer never blocks the sender.
+//
+// Dropped events are additionally counted by the "event/buffered/<name>/dropped"
+// meter of the default metrics registry, shared by all subscriptions of the same
+// name, so slow consumers of a feed can be monitored.
+//
+// If the source returns a nil subscription, nil is returned.
+func SubscribeBuffered[T any](name string, subscribe func(chan<- T) Subscription, channel chan<- T, size int) *BufferedSubscription {
+	if size <= 0 {
+		size = 1
+	}
+	in := make(chan T)
+	sub := subscribe(in)
+	if sub == nil {
+		return nil
+	}
+	var (
+		bs    = new(BufferedSubscription)
+		meter = metrics.GetOrRegisterMeter("event/buffered/"+name+"/dropped", nil)
+	)
+	bs.Subscription = NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+
+		queue := make([]T, 0, size)
+		for {
+			// Only enable the delivery case if there's something to deliver
+			var (
+				out  chan<- T
+				next T
+			)
+			if len(queue) > 0 {
+				out, next = channel, queue[0]
+			}
+			select {
+			case event := <-in:
+				if len(queue) >= size {
+					queue = queue[1:]
+					bs.dropped.Add(1)
+					meter.Mark(1)
+				}
+				queue = append(queue, event)
+
+			case out <- next:
+				var zero T
+				queue[0] = zero // Release the reference for the GC
+				queue = queue[1:]
+
+			case err := <-sub.Err():
+				return err
+
+			case <-quit:
+				return nil
+			}
+		}
+	})
+	return bs
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+func TestBufferedSubscription(t *testing.T) {
+	var (
+		feed FeedOf[int]
+		ch   = make(chan int)
+		sub  = SubscribeBuffered("test", feed.Subscribe, ch, 4)
+	)
+	defer sub.Unsubscribe()
+
+	// The relay is subscribed to the feed by the time the call returns
+	if n := feed.Send(0); n != 1 {
+		t.Fatalf("subscriber count mismatch: have %d, want %d", n, 1)
+	}
+	<-ch
+
+	// Send more events than the buffer can hold, without consuming any. None
+	// of the sends may block even though the consumer is stalled.
+	done := make(chan struct{})
+	go func() {
+		for i := 1; i <= 10; i++ {
+			feed.Send(i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("feed send blocked by slow subscriber")
+	}
+	// Wait for the relay to process all the events, then check that only the
+	// most recent ones were retained.
+	for sub.Dropped() < 6 {
+		time.Sleep(time.Millisecond)
+	}
+	for want := 7; want <= 10; want++ {
+		if have := <-ch; have != want {
+			t.Fatalf("event mismatch: have %d, want %d", have, want)
+		}
+	}
+	if dropped := sub.Dropped(); dropped != 6 {
+		t.Fatalf("dropped count mismatch: have %d, want %d", dropped, 6)
+	}
+}
+
+func TestBufferedSubscriptionDropMeter(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	var (
+		feed  FeedOf[int]
+		ch    = make(chan int)
+		meter = metrics.GetOrRegisterMeter("event/buffered/test/meter/dropped", nil)
+		start = meter.Snapshot().Count()
+		sub   = SubscribeBuffered("test/meter", feed.Subscribe, ch, 1)
+	)
+	defer sub.Unsubscribe()
+
+	for i := 0; i < 3; i++ {
+		feed.Send(i)
+	}
+	for sub.Dropped() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if count := meter.Snapshot().Count() - start; count != 2 {
+		t.Fatalf("drop meter mismatch: have %d, want %d", count, 2)
+	}
+}
+
+func TestBufferedSubscriptionNilSource(t *testing.T) {
+	sub := SubscribeBuffered("test/nil", func(chan<- int) Subscription { return nil }, make(chan int), 1)
+	if sub != nil {
+		t.Fatalf("buffered subscription created over a nil source")
+	}
+}
@@ -176,6 +176,47 @@ func TestGraphQLBlockSerialization(t *testing.T) {
 	}
 }
 
+// Tests that the call and estimateGas fields obey the per-method limits of the
+// corresponding JSON-RPC methods.
+func TestGraphQLMethodLimits(t *testing.T) {
+	stack := createNode(t)
+	defer stack.Close()
+	genesis := &core.Genesis{
+		Config:     params.AllEthashProtocolChanges,
+		GasLimit:   11500000,
+		Difficulty: big.NewInt(1048576),
+	}
+	lowCap := uint64(1000) // Below the intrinsic gas of any transaction
+	newGQLServiceWithConfig(t, stack, false, genesis, 1, func(i int, gen *core.BlockGen) {}, func(config *ethconfig.Config) {
+		config.RPCMethodLimits = map[string]ethconfig.RPCLimits{
+			"eth_call":        {GasCap: &lowCap},
+			"eth_estimateGas": {GasCap: &lowCap},
+		}
+	})
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	for i, body := range []string{
+		`{"query": "{block{ call(data:{}) { status } }}"}`,
+		`{"query": "{block{ estimateGas(data:{}) }}"}`,
+		`{"query": "{pending{ call(data:{}) { status } }}"}`,
+		`{"query": "{pending{ estimateGas(data:{}) }}"}`,
+	} {
+		resp, err := http.Post(fmt.Sprintf("%s/graphql", stack.HTTPEndpoint()), "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("could not post: %v", err)
+		}
+		bodyBytes, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("could not read from response body: %v", err)
+		}
+		if have := string(bodyBytes); !strings.Contains(have, `"errors"`) {
+			t.Errorf("testcase %d %s: method gas cap not applied, have %v", i, body, have)
+		}
+	}
+}
+
 func TestGraphQLBlockSerializationEIP2718(t *testing.T) {
 	// Account for signing txes
 	var (
@@ -446,6 +487,10 @@ func createNode(t *testing.T) *node.Node {
 }
 
 func newGQLService(t *testing.T, stack *node.Node, shanghai bool, gspec *core.Genesis, genBlocks int, genfunc func(i int, gen *core.BlockGen)) (*handler, []*types.Block) {
+	return newGQLServiceWithConfig(t, stack, shanghai, gspec, genBlocks, genfunc, nil)
+}
+
+func newGQLServiceWithConfig(t *testing.T, stack *node.Node, shanghai bool, gspec *core.Genesis, genBlocks int, genfunc func(i int, gen *core.BlockGen), configure func(*ethconfig.Config)) (*handler, []*types.Block) {
 	ethConf := &ethconfig.Config{
 		Genesis:        gspec,
 		NetworkId:      1337,
@@ -456,6 +501,9 @@ func newGQLService(t *testing.T, stack *node.Node, shanghai bool, gspec *core.Ge
 		RPCGasCap:      1000000,
 		StateScheme:    rawdb.HashScheme,
 	}
+	if configure != nil {
+		configure(ethConf)
+	}
 	var engine consensus.Engine = ethash.NewFaker()
 	if shanghai {
 		engine = beacon.NewFaker()
@@ -0,0 +1,130 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package caches implements a registry of the node's in-memory caches, so their
+// usage statistics can be inspected from a single place.
+package caches
+
+import (
+	"sync"
+
+	"github.com/VictoriaMetrics/fastcache"
+	"github.com/ethereum/go-ethereum/common/lru"
+)
+
+// Units in which the size and capacity of caches are reported.
+const (
+	UnitItems = "items"
+	UnitBytes = "bytes"
+)
+
+// Stats is a snapshot of the usage statistics of a cache.
+type Stats struct {
+	Items     uint64  `json:"items"`     // Number of entries in the cache
+	Size      uint64  `json:"size"`      // Current size of the cache, measured in Unit
+	Capacity  uint64  `json:"capacity"`  // Maximum size of the cache, measured in Unit
+	Unit      string  `json:"unit"`      // Unit of size and capacity (items or bytes)
+	Hits      uint64  `json:"hits"`      // Number of lookups which found their entry
+	Misses    uint64  `json:"misses"`    // Number of lookups which didn't find their entry
+	Evictions uint64  `json:"evictions"` // Number of entries dropped to make room, if tracked
+	HitRatio  float64 `json:"hitRatio"`  // Ratio of hits among all lookups
+}
+
+// StatsFunc retrieves the current statistics of a cache.
+type StatsFunc func() Stats
+
+type entry struct {
+	stats StatsFunc
+}
+
+var (
+	lock     sync.Mutex
+	registry = make(map[string]*entry)
+)
+
+// Register adds a cache to the registry under the given name, replacing any
+// cache previously registered with the same name. The returned function removes
+// the registration, unless it was replaced in the meantime.
+func Register(name string, stats StatsFunc) (unregister func()) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	e := &entry{stats: stats}
+	registry[name] = e
+
+	return func() {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if registry[name] == e {
+			delete(registry, name)
+		}
+	}
+}
+
+// Collect returns the current statistics of all registered caches.
+func Collect() map[string]Stats {
+	lock.Lock()
+	entries := make(map[string]*entry, len(registry))
+	for name, e := range registry {
+		entries[name] = e
+	}
+	lock.Unlock()
+
+	stats := make(map[string]Stats, len(entries))
+	for name, e := range entries {
+		s := e.stats()
+		if lookups := s.Hits + s.Misses; lookups > 0 {
+			s.HitRatio = float64(s.Hits) / float64(lookups)
+		}
+		stats[name] = s
+	}
+	return stats
+}
+
+// LRU returns a statistics retriever for an LRU cache, whose size is measured
+// in the given unit.
+func LRU(unit string, stats func() lru.Stats) StatsFunc {
+	return func() Stats {
+		s := stats()
+		return Stats{
+			Items:     s.Items,
+			Size:      s.Size,
+			Capacity:  s.Capacity,
+			Unit:      unit,
+			Hits:      s.Hits,
+			Misses:    s.Misses,
+			Evictions: s.Evictions,
+		}
+	}
+}
+
+// Fastcache converts the statistics of a fastcache instance. Fastcache does not
+// track evictions, so they are always reported as zero.
+func Fastcache(s *fastcache.Stats) Stats {
+	var hits uint64
+	if s.GetCalls > s.Misses {
+		hits = s.GetCalls - s.Misses
+	}
+	return Stats{
+		Items:    s.EntriesCount,
+		Size:     s.BytesSize,
+		Capacity: s.MaxBytesSize,
+		Unit:     UnitBytes,
+		Hits:     hits,
+		Misses:   s.Misses,
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package caches
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+)
+
+func TestRegistry(t *testing.T) {
+	cache := lru.NewCache[int, int](4)
+	cache.Add(1, 1)
+	cache.Get(1)
+	cache.Get(1)
+	cache.Get(1)
+	cache.Get(2)
+
+	unregister := Register("test", LRU(UnitItems, cache.Stats))
+
+	stats, ok := Collect()["test"]
+	if !ok {
+		t.Fatal("registered cache missing")
+	}
+	want := Stats{Items: 1, Size: 1, Capacity: 4, Unit: UnitItems, Hits: 3, Misses: 1, HitRatio: 0.75}
+	if stats != want {
+		t.Fatalf("stats mismatch: have %+v, want %+v", stats, want)
+	}
+	// A replaced registration must not be removed by the stale unregister
+	unregisterNew := Register("test", LRU(UnitItems, cache.Stats))
+	unregister()
+	if _, ok := Collect()["test"]; !ok {
+		t.Fatal("replacement registration removed")
+	}
+	unregisterNew()
+	if _, ok := Collect()["test"]; ok {
+		t.Fatal("cache still registered")
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package chainexport implements a segmented export format for the canonical
+// chain. Blocks are written in fixed size segments, each carrying a checksum,
+// so that an import can verify the data as it goes and an interrupted import
+// can be resumed after the last segment already present in the database.
+//
+// The stream is a sequence of RLP items: a header followed by the segments.
+package chainexport
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// exportMagic disambiguates segmented exports from plain RLP block dumps.
+	exportMagic = "gethchainexport"
+
+	// exportVersion is the format version, bumped on incompatible changes.
+	exportVersion = 0
+
+	// DefaultSegmentSize is the number of blocks per segment.
+	DefaultSegmentSize = 1024
+)
+
+var (
+	// ErrInvalidMagic is returned if the stream is not a segmented export.
+	ErrInvalidMagic = errors.New("not a segmented chain export")
+
+	// ErrGenesisMismatch is returned if the export belongs to a different chain.
+	ErrGenesisMismatch = errors.New("genesis mismatch")
+
+	// ErrChecksumMismatch is returned if a segment fails its integrity check.
+	ErrChecksumMismatch = errors.New("segment checksum mismatch")
+
+	// ErrTruncated is returned if the stream ends before the last block.
+	ErrTruncated = errors.New("export truncated")
+)
+
+// header is the first item of an export.
+type header struct {
+	Magic       string
+	Version     uint64
+	Genesis     common.Hash
+	First       uint64
+	Last        uint64
+	SegmentSize uint64
+}
+
+// segments returns the number of segments in the export.
+func (h *header) segments() uint64 {
+	return (h.Last-h.First)/h.SegmentSize + 1
+}
+
+// segment is a run of consecutive blocks along with its checksum.
+type segment struct {
+	Index    uint64
+	Blocks   []rlp.RawValue
+	Checksum common.Hash
+}
+
+// checksum computes the integrity hash of a segment, covering its position in
+// the export and the encoding of all its blocks.
+func checksum(index uint64, blocks []rlp.RawValue) common.Hash {
+	hasher := crypto.NewKeccakState()
+	hasher.Write(binary.BigEndian.AppendUint64(nil, index))
+	for _, block := range blocks {
+		hasher.Write(block)
+	}
+	var hash common.Hash
+	hasher.Read(hash[:])
+	return hash
+}
+
+// Progress is reported after each segment processed by an export or import.
+type Progress struct {
+	Segment  uint64 // Index of the processed segment
+	Segments uint64 // Total number of segments
+	Number   uint64 // Number of the last block in the segment
+	Skipped  bool   // Whether the segment was already present (import only)
+}
+
+// ProgressFunc is a callback invoked with the progress of an export or import.
+type ProgressFunc func(Progress)
+
+// ChainReader is the chain access needed to export blocks.
+type ChainReader interface {
+	Genesis() *types.Block
+	GetBlockByNumber(number uint64) *types.Block
+}
+
+// ChainWriter is the chain access needed to import blocks.
+type ChainWriter interface {
+	Genesis() *types.Block
+	HasBlock(hash common.Hash, number uint64) bool
+	InsertChain(blocks types.Blocks) (int, error)
+}
+
+// Export writes the canonical blocks in the range [first, last] to w, split in
+// segments of segmentSize blocks. Zero selects DefaultSegmentSize.
+func Export(ctx context.Context, chain ChainReader, w io.Writer, first, last, segmentSize uint64, progress ProgressFunc) error {
+	if first > last {
+		return fmt.Errorf("first (%d) is greater than last (%d)", first, last)
+	}
+	if segmentSize == 0 {
+		segmentSize = DefaultSegmentSize
+	}
+	head := &header{
+		Magic:       exportMagic,
+		Version:     exportVersion,
+		Genesis:     chain.Genesis().Hash(),
+		First:       first,
+		Last:        last,
+		SegmentSize: segmentSize,
+	}
+	if err := rlp.Encode(w, head); err != nil {
+		return err
+	}
+	var parent common.Hash
+	for index := uint64(0); index < head.segments(); index++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var (
+			start = first + index*segmentSize
+			end   = min(start+segmentSize-1, last)
+			seg   = &segment{Index: index, Blocks: make([]rlp.RawValue, 0, end-start+1)}
+		)
+		for number := start; number <= end; number++ {
+			block := chain.GetBlockByNumber(number)
+			if block == nil {
+				return fmt.Errorf("block #%d not found", number)
+			}
+			if number > first && block.ParentHash() != parent {
+				return errors.New("chain reorg during export")
+			}
+			parent = block.Hash()
+
+			enc, err := rlp.EncodeToBytes(block)
+			if err != nil {
+				return err
+			}
+			seg.Blocks = append(seg.Blocks, enc)
+		}
+		seg.Checksum = checksum(index, seg.Blocks)
+		if err := rlp.Encode(w, seg); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(Progress{Segment: index, Segments: head.segments(), Number: end})
+		}
+	}
+	return nil
+}
+
+// Import reads a segmented export from r and inserts its blocks into the chain.
+// Every segment is verified before any of its blocks are inserted. Segments
+// which are already fully present in the chain are skipped, so an interrupted
+// import can be resumed by running it again on the same export.
+func Import(ctx context.Context, chain ChainWriter, r io.Reader, progress ProgressFunc) error {
+	stream := rlp.NewStream(r, 0)
+
+	var head header
+	if err := stream.Decode(&head); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMagic, err)
+	}
+	if head.Magic != exportMagic {
+		return ErrInvalidMagic
+	}
+	if head.Version != exportVersion {
+		return fmt.Errorf("unsupported export version %d", head.Version)
+	}
+	if head.First > head.Last || head.SegmentSize == 0 {
+		return fmt.Errorf("invalid export range [%d, %d], segment size %d", head.First, head.Last, head.SegmentSize)
+	}
+	if genesis := chain.Genesis().Hash(); head.Genesis != genesis {
+		return fmt.Errorf("%w: export %x, chain %x", ErrGenesisMismatch, head.Genesis, genesis)
+	}
+	for index := uint64(0); index < head.segments(); index++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var seg segment
+		if err := stream.Decode(&seg); err != nil {
+			return fmt.Errorf("%w: segment %d: %v", ErrTruncated, index, err)
+		}
+		blocks, err := verify(&head, index, &seg)
+		if err != nil {
+			return err
+		}
+		var (
+			last    = blocks[len(blocks)-1]
+			skipped = chain.HasBlock(last.Hash(), last.NumberU64())
+		)
+		if !skipped {
+			// Skip over the blocks already present, which may happen when
+			// resuming after a failure in the middle of the segment
+			start := 0
+			for start < len(blocks) && (blocks[start].NumberU64() == 0 || chain.HasBlock(blocks[start].Hash(), blocks[start].NumberU64())) {
+				start++
+			}
+			if n, err := chain.InsertChain(blocks[start:]); err != nil {
+				return fmt.Errorf("invalid block #%d: %v", blocks[start+n].NumberU64(), err)
+			}
+		}
+		if progress != nil {
+			progress(Progress{Segment: index, Segments: head.segments(), Number: last.NumberU64(), Skipped: skipped})
+		}
+	}
+	return nil
+}
+
+// verify checks the integrity of a segment and decodes its blocks.
+func verify(head *header, index uint64, seg *segment) (types.Blocks, error) {
+	if seg.Index != index {
+		return nil, fmt.Errorf("segment out of order: have %d, want %d", seg.Index, index)
+	}
+	if checksum(index, seg.Blocks) != seg.Checksum {
+		return nil, fmt.Errorf("%w: segment %d", ErrChecksumMismatch, index)
+	}
+	var (
+		start = head.First + index*head.SegmentSize
+		end   = min(start+head.SegmentSize-1, head.Last)
+	)
+	if uint64(len(seg.Blocks)) != end-start+1 {
+		return nil, fmt.Errorf("segment %d: have %d blocks, want %d", index, len(seg.Blocks), end-start+1)
+	}
+	blocks := make(types.Blocks, len(seg.Blocks))
+	for i, enc := range seg.Blocks {
+		block := new(types.Block)
+		if err := rlp.DecodeBytes(enc, block); err != nil {
+			return nil, fmt.Errorf("segment %d: block #%d: %v", index, start+uint64(i), err)
+		}
+		if block.NumberU64() != start+uint64(i) {
+			return nil, fmt.Errorf("segment %d: have block #%d, want #%d", index, block.NumberU64(), start+uint64(i))
+		}
+		if i > 0 && block.ParentHash() != blocks[i-1].Hash() {
+			return nil, fmt.Errorf("segment %d: block #%d is not linked to its parent", index, block.NumberU64())
+		}
+		blocks[i] = block
+	}
+	return blocks, nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package chainexport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newTestChains creates a source chain with n blocks and an empty destination
+// chain sharing the same genesis.
+func newTestChains(t *testing.T, n int) (*core.BlockChain, *core.BlockChain) {
+	t.Helper()
+
+	genesis := &core.Genesis{
+		Config:  params.TestChainConfig,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), n, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{byte(i)})
+	})
+	src, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create source chain: %v", err)
+	}
+	t.Cleanup(src.Stop)
+	if _, err := src.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert source chain: %v", err)
+	}
+	dst, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create destination chain: %v", err)
+	}
+	t.Cleanup(dst.Stop)
+	return src, dst
+}
+
+func TestExportImport(t *testing.T) {
+	src, dst := newTestChains(t, 10)
+
+	var (
+		buf      bytes.Buffer
+		exported []Progress
+	)
+	err := Export(context.Background(), src, &buf, 0, 10, 4, func(p Progress) { exported = append(exported, p) })
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if len(exported) != 3 || exported[2].Number != 10 || exported[2].Segments != 3 {
+		t.Fatalf("unexpected export progress: %+v", exported)
+	}
+	var imported []Progress
+	if err := Import(context.Background(), dst, bytes.NewReader(buf.Bytes()), func(p Progress) { imported = append(imported, p) }); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if head := dst.CurrentBlock(); head.Hash() != src.CurrentBlock().Hash() {
+		t.Fatalf("head mismatch: have #%d, want #%d", head.Number, src.CurrentBlock().Number)
+	}
+	if len(imported) != 3 {
+		t.Fatalf("unexpected import progress: %+v", imported)
+	}
+	for _, p := range imported {
+		if p.Skipped {
+			t.Fatalf("segment %d unexpectedly skipped", p.Segment)
+		}
+	}
+}
+
+func TestImportResume(t *testing.T) {
+	src, dst := newTestChains(t, 10)
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), src, &buf, 0, 10, 4, nil); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	// Cut the export in the middle of the last segment
+	truncated := buf.Bytes()[:buf.Len()-100]
+	if err := Import(context.Background(), dst, bytes.NewReader(truncated), nil); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("truncated import error mismatch: have %v, want %v", err, ErrTruncated)
+	}
+	if head := dst.CurrentBlock().Number.Uint64(); head != 7 {
+		t.Fatalf("head after truncated import mismatch: have #%d, want #7", head)
+	}
+	// Rerun with the full export, the verified segments must be skipped
+	var progress []Progress
+	if err := Import(context.Background(), dst, bytes.NewReader(buf.Bytes()), func(p Progress) { progress = append(progress, p) }); err != nil {
+		t.Fatalf("resumed import failed: %v", err)
+	}
+	if len(progress) != 3 || !progress[0].Skipped || !progress[1].Skipped || progress[2].Skipped {
+		t.Fatalf("unexpected resume progress: %+v", progress)
+	}
+	if head := dst.CurrentBlock(); head.Hash() != src.CurrentBlock().Hash() {
+		t.Fatalf("head mismatch: have #%d, want #%d", head.Number, src.CurrentBlock().Number)
+	}
+}
+
+func TestImportCorrupted(t *testing.T) {
+	src, dst := newTestChains(t, 10)
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), src, &buf, 1, 10, 4, nil); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	// Flip a byte inside the last block, which is part of the last segment
+	corrupted := bytes.Clone(buf.Bytes())
+	corrupted[len(corrupted)-50] ^= 0xff
+
+	if err := Import(context.Background(), dst, bytes.NewReader(corrupted), nil); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("corrupted import error mismatch: have %v, want %v", err, ErrChecksumMismatch)
+	}
+	// Nothing of the corrupted segment may have been imported
+	if head := dst.CurrentBlock().Number.Uint64(); head != 8 {
+		t.Fatalf("head after corrupted import mismatch: have #%d, want #8", head)
+	}
+}
+
+func TestImportGenesisMismatch(t *testing.T) {
+	src, _ := newTestChains(t, 2)
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), src, &buf, 0, 2, 0, nil); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	genesis := &core.Genesis{Config: params.TestChainConfig, ExtraData: []byte("other")}
+	other, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer other.Stop()
+
+	if err := Import(context.Background(), other, bytes.NewReader(buf.Bytes()), nil); !errors.Is(err, ErrGenesisMismatch) {
+		t.Fatalf("import error mismatch: have %v, want %v", err, ErrGenesisMismatch)
+	}
+	if err := Import(context.Background(), other, bytes.NewReader([]byte{0xc0}), nil); !errors.Is(err, ErrInvalidMagic) {
+		t.Fatalf("import error mismatch: have %v, want %v", err, ErrInvalidMagic)
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package crash implements panic recovery for long-running subsystems, saving
+// a crash report bundle to disk for every recovered panic.
+//
+// A bundle is a directory containing the panic value and stack, a dump of all
+// goroutines, the most recent log records, the node configuration and any
+// subsystem specific details, such as the block being processed.
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Details is a callback assembling subsystem specific information about the
+// failure, e.g. the block or message being processed. It is only invoked after
+// a panic, so it may be arbitrarily expensive.
+type Details func() map[string]interface{}
+
+var (
+	lock   sync.Mutex
+	dir    string // Directory to save crash bundles into, empty = disabled
+	config []byte // Serialized node configuration to include in crash bundles
+)
+
+// Configure sets the directory crash bundles are saved into along with the
+// serialized configuration to include in them. The configuration must not
+// contain any secrets. An empty directory disables writing bundles; recovered
+// panics are still logged.
+func Configure(bundleDir string, cfg []byte) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	dir, config = bundleDir, cfg
+}
+
+// Handle must be deferred directly. It saves a crash bundle for a panic in the
+// subsystem and then resumes panicking. It is meant for subsystems which can't
+// be safely restarted, where the node must still go down.
+func Handle(subsystem string, details Details) {
+	if r := recover(); r != nil {
+		Report(subsystem, r, debug.Stack(), details)
+		panic(r)
+	}
+}
+
+// Guard runs fn, recovering any panic it raises and saving a crash bundle for
+// it. The return value reports whether fn panicked.
+func Guard(subsystem string, details Details, fn func()) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			Report(subsystem, r, debug.Stack(), details)
+			panicked = true
+		}
+	}()
+	fn()
+	return false
+}
+
+// Supervise runs fn, restarting it after a panic until it returns normally,
+// the quit channel is closed or the restart limit is reached. Restarts are
+// delayed by an exponential backoff. It must only be used for subsystems which
+// hold no state that a panic could leave inconsistent.
+func Supervise(subsystem string, restarts int, quit <-chan struct{}, details Details, fn func()) {
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		if !Guard(subsystem, details, fn) {
+			return
+		}
+		if attempt >= restarts {
+			log.Error("Subsystem crashed too often, not restarting", "subsystem", subsystem, "restarts", attempt)
+			return
+		}
+		log.Warn("Restarting crashed subsystem", "subsystem", subsystem, "attempt", attempt+1, "delay", backoff)
+		select {
+		case <-time.After(backoff):
+		case <-quit:
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// Report saves a crash bundle for a recovered panic and returns its path. An
+// empty path is returned if bundles are disabled or the bundle could not be
+// written, in which case the panic is only logged.
+func Report(subsystem string, value interface{}, stack []byte, details Details) string {
+	log.Error("Recovered panic in subsystem", "subsystem", subsystem, "err", value)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if dir == "" {
+		log.Error("Crash bundles disabled, dumping stack", "subsystem", subsystem, "stack", string(stack))
+		return ""
+	}
+	path, err := writeBundle(dir, subsystem, value, stack, details)
+	if err != nil {
+		log.Error("Failed to write crash bundle", "subsystem", subsystem, "err", err)
+		return ""
+	}
+	log.Error("Crash bundle written", "subsystem", subsystem, "path", path)
+	return path
+}
+
+// unsafeNameChars matches the characters not allowed in bundle directory names.
+var unsafeNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// writeBundle writes a crash bundle into a new directory within root.
+func writeBundle(root string, subsystem string, value interface{}, stack []byte, details Details) (string, error) {
+	name := fmt.Sprintf("%s-%s", time.Now().UTC().Format("20060102T150405.000000000"), unsafeNameChars.ReplaceAllString(subsystem, "_"))
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return "", err
+	}
+	files := map[string][]byte{
+		"panic.txt":      []byte(fmt.Sprintf("subsystem: %s\npanic: %v\n\n%s", subsystem, value, stack)),
+		"goroutines.txt": goroutines(),
+		"logs.txt":       recent.dump(),
+		"config.toml":    config,
+	}
+	if details != nil {
+		files["details.json"] = marshal(collect(details))
+	}
+	for file, data := range files {
+		if err := os.WriteFile(filepath.Join(path, file), data, 0600); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+// collect invokes the details callback, guarding against it panicking too.
+func collect(details Details) (info map[string]interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			info = map[string]interface{}{"error": fmt.Sprintf("details unavailable: %v", r)}
+		}
+	}()
+	return details()
+}
+
+// marshal encodes v as indented JSON, falling back to an error description if
+// it cannot be encoded.
+func marshal(v interface{}) []byte {
+	blob, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return []byte(fmt.Sprintf("%q\n", fmt.Sprintf("failed to encode: %v", err)))
+	}
+	return append(blob, '\n')
+}
+
+// goroutines returns the stack traces of all goroutines.
+func goroutines() []byte {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		if len(buf) >= 64<<20 {
+			return buf // Truncate absurdly large dumps
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package crash
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+func TestGuardWritesBundle(t *testing.T) {
+	dir := t.TempDir()
+	Configure(dir, []byte("[Eth]\nNetworkId = 1\n"))
+	defer Configure("", nil)
+
+	logger := log.NewLogger(NewLogRecorder(log.NewTerminalHandler(io.Discard, false)))
+	logger.Info("Importing block", "number", 42)
+
+	details := func() map[string]interface{} { return map[string]interface{}{"number": 42} }
+	if !Guard("block import", details, func() { panic("boom") }) {
+		t.Fatal("panic not reported")
+	}
+	if Guard("block import", details, func() {}) {
+		t.Fatal("panic reported for successful run")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("bundle count mismatch: have %d, want 1", len(entries))
+	}
+	if name := entries[0].Name(); !strings.HasSuffix(name, "-block_import") {
+		t.Fatalf("unexpected bundle name: %s", name)
+	}
+	contents := map[string]string{
+		"panic.txt":      "panic: boom",
+		"goroutines.txt": "goroutine",
+		"logs.txt":       "Importing block number=42",
+		"config.toml":    "NetworkId = 1",
+		"details.json":   `"number": 42`,
+	}
+	for file, want := range contents {
+		blob, err := os.ReadFile(filepath.Join(dir, entries[0].Name(), file))
+		if err != nil {
+			t.Fatalf("missing bundle file %s: %v", file, err)
+		}
+		if !strings.Contains(string(blob), want) {
+			t.Errorf("bundle file %s missing %q", file, want)
+		}
+	}
+}
+
+func TestHandleRepanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Fatalf("panic value mismatch: have %v, want boom", r)
+		}
+	}()
+	func() {
+		defer Handle("test", nil)
+		panic("boom")
+	}()
+}
+
+func TestSuperviseRestarts(t *testing.T) {
+	var runs int
+	Supervise("test", 1, nil, nil, func() {
+		if runs++; runs == 1 {
+			panic("boom")
+		}
+	})
+	if runs != 2 {
+		t.Fatalf("run count mismatch: have %d, want 2", runs)
+	}
+	// Exceeding the restart limit must give up
+	runs = 0
+	Supervise("test", 0, nil, nil, func() {
+		runs++
+		panic("boom")
+	})
+	if runs != 1 {
+		t.Fatalf("run count mismatch: have %d, want 1", runs)
+	}
+}
+
+func TestLogRing(t *testing.T) {
+	r := &logRing{lines: make([][]byte, 3)}
+	for _, line := range []string{"a\n", "b\n", "c\n", "d\n"} {
+		r.add([]byte(line))
+	}
+	if have := string(r.dump()); have != "b\nc\nd\n" {
+		t.Fatalf("ring contents mismatch: have %q, want %q", have, "b\nc\nd\n")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package crash
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// recentLogs is the number of log records retained for crash bundles.
+const recentLogs = 1000
+
+// recent is the log history shared by all recording handlers.
+var recent = &logRing{lines: make([][]byte, recentLogs)}
+
+// logRing is a fixed size ring buffer of formatted log records.
+type logRing struct {
+	lock  sync.Mutex
+	lines [][]byte
+	next  int
+	full  bool
+}
+
+// add appends a formatted record to the ring, evicting the oldest if full.
+func (r *logRing) add(line []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// dump returns the retained records in chronological order.
+func (r *logRing) dump() []byte {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var buf bytes.Buffer
+	if r.full {
+		for _, line := range r.lines[r.next:] {
+			buf.Write(line)
+		}
+	}
+	for _, line := range r.lines[:r.next] {
+		buf.Write(line)
+	}
+	return buf.Bytes()
+}
+
+// recorder is a log handler retaining the recent records for crash bundles
+// before passing them on to the wrapped handler.
+type recorder struct {
+	inner slog.Handler
+	attrs []slog.Attr
+}
+
+// NewLogRecorder wraps a log handler, retaining the records it handles so they
+// can be included in crash bundles.
+func NewLogRecorder(inner slog.Handler) slog.Handler {
+	return &recorder{inner: inner}
+}
+
+func (h *recorder) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *recorder) Handle(ctx context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	buf.WriteString(r.Time.UTC().Format(time.RFC3339Nano))
+	buf.WriteByte(' ')
+	buf.WriteString(r.Level.String())
+	buf.WriteByte(' ')
+	buf.WriteString(r.Message)
+	for _, attr := range h.attrs {
+		buf.WriteByte(' ')
+		buf.WriteString(attr.String())
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		buf.WriteByte(' ')
+		buf.WriteString(attr.String())
+		return true
+	})
+	buf.WriteByte('\n')
+	recent.add(buf.Bytes())
+
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &recorder{
+		inner: h.inner.WithAttrs(attrs),
+		attrs: append(slices.Clone(h.attrs), attrs...),
+	}
+}
+
+func (h *recorder) WithGroup(name string) slog.Handler {
+	return &recorder{inner: h.inner.WithGroup(name), attrs: h.attrs}
+}
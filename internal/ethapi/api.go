@@ -38,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -246,6 +247,19 @@ func (api *TxPoolAPI) Status() map[string]hexutil.Uint {
 	}
 }
 
+// TransactionStatus reports whether the transaction with the given hash is
+// pending or queued in the pool, or unknown to it.
+func (api *TxPoolAPI) TransactionStatus(hash common.Hash) string {
+	switch api.b.GetPoolTransactionStatus(hash) {
+	case txpool.TxStatusPending:
+		return TxLifecyclePending
+	case txpool.TxStatusQueued:
+		return TxLifecycleQueued
+	default:
+		return TxLifecycleUnknown
+	}
+}
+
 // Inspect retrieves the content of the transaction pool and flattens it into an
 // easily inspectable list.
 func (api *TxPoolAPI) Inspect() map[string]map[string]map[string]string {
@@ -719,9 +733,21 @@ func (api *BlockChainAPI) GetProof(ctx context.Context, address common.Address,
 		}
 	}
 	statedb, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
-	if statedb == nil || err != nil {
+	if err != nil {
+		// If the block exists but its state root is missing, it was most probably
+		// pruned. Report it explicitly as historical proofs are only served by
+		// archive nodes. Any other failure is passed through as is.
+		var missing *trie.MissingNodeError
+		if errors.As(err, &missing) {
+			if header, _ := api.b.HeaderByNumberOrHash(ctx, blockNrOrHash); header != nil {
+				return nil, &stateUnavailableError{fmt.Sprintf("state of block #%d is not available, it may have been pruned: %v", header.Number, err)}
+			}
+		}
 		return nil, err
 	}
+	if statedb == nil {
+		return nil, nil
+	}
 	codeHash := statedb.GetCodeHash(address)
 	storageRoot := statedb.GetStorageRoot(address)
 
@@ -1257,7 +1283,8 @@ func (api *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockN
 		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
 		blockNrOrHash = &latest
 	}
-	result, err := DoCall(ctx, api.b, args, *blockNrOrHash, overrides, blockOverrides, api.b.RPCEVMTimeout(), api.b.RPCGasCap())
+	gasCap, timeout := api.b.RPCMethodLimits("eth_call")
+	result, err := DoCall(ctx, api.b, args, *blockNrOrHash, overrides, blockOverrides, timeout, gasCap)
 	if err != nil {
 		return nil, err
 	}
@@ -1289,7 +1316,7 @@ func (api *BlockChainAPI) SimulateV1(ctx context.Context, opts simOpts, blockNrO
 	if state == nil || err != nil {
 		return nil, err
 	}
-	gasCap := api.b.RPCGasCap()
+	gasCap, timeout := api.b.RPCMethodLimits("eth_simulateV1")
 	if gasCap == 0 {
 		gasCap = math.MaxUint64
 	}
@@ -1303,6 +1330,7 @@ func (api *BlockChainAPI) SimulateV1(ctx context.Context, opts simOpts, blockNrO
 		traceTransfers: opts.TraceTransfers,
 		validate:       opts.Validation,
 		fullTx:         opts.ReturnFullTransactions,
+		timeout:        timeout,
 	}
 	return sim.execute(ctx, opts.BlockStateCalls)
 }
@@ -1360,7 +1388,25 @@ func (api *BlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs,
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
 	}
-	return DoEstimateGas(ctx, api.b, args, bNrOrHash, overrides, api.b.RPCGasCap())
+	return DoEstimateGasWithLimits(ctx, api.b, args, bNrOrHash, overrides)
+}
+
+// DoEstimateGasWithLimits estimates the gas needed by a transaction within the
+// gas cap and EVM timeout configured for the eth_estimateGas method.
+func DoEstimateGasWithLimits(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride) (hexutil.Uint64, error) {
+	gasCap, timeout := b.RPCMethodLimits("eth_estimateGas")
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	estimate, err := DoEstimateGas(ctx, b, args, blockNrOrHash, overrides, gasCap)
+	if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		// An interrupted execution looks successful to the estimator, discard
+		// whatever result was produced and report the timeout instead.
+		return 0, fmt.Errorf("gas estimation aborted (timeout = %v)", timeout)
+	}
+	return estimate, err
 }
 
 // RPCMarshalHeader converts the given header to the RPC output .
@@ -2236,22 +2282,12 @@ func (api *DebugAPI) ChaindbProperty() (string, error) {
 }
 
 // ChaindbCompact flattens the entire key-value database into a single level,
-// removing all unused slots and merging all keys.
+// removing all unused slots and merging all keys. It fails if another
+// compaction of the database is already in progress.
 func (api *DebugAPI) ChaindbCompact() error {
-	cstart := time.Now()
-	for b := 0; b <= 255; b++ {
-		var (
-			start = []byte{byte(b)}
-			end   = []byte{byte(b + 1)}
-		)
-		if b == 255 {
-			end = nil
-		}
-		log.Info("Compacting database", "range", fmt.Sprintf("%#X-%#X", start, end), "elapsed", common.PrettyDuration(time.Since(cstart)))
-		if err := api.b.ChainDb().Compact(start, end); err != nil {
-			log.Error("Database compaction failed", "err", err)
-			return err
-		}
+	if err := api.b.CompactDatabase(nil, nil); err != nil {
+		log.Error("Database compaction failed", "err", err)
+		return err
 	}
 	return nil
 }
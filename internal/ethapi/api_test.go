@@ -31,6 +31,7 @@ import (
 	"reflect"
 	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -46,6 +47,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -55,6 +57,7 @@ import (
 	"github.com/ethereum/go-ethereum/internal/blocktest"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/require"
@@ -479,6 +482,7 @@ func (b testBackend) FeeHistory(ctx context.Context, blockCount uint64, lastBloc
 }
 func (b testBackend) BlobBaseFee(ctx context.Context) *big.Int { return new(big.Int) }
 func (b testBackend) ChainDb() ethdb.Database                  { return b.db }
+func (b testBackend) CompactDatabase(start, end []byte) error  { return b.db.Compact(start, end) }
 func (b testBackend) AccountManager() *accounts.Manager        { return b.accman }
 func (b testBackend) ExtRPCEnabled() bool                      { return false }
 func (b testBackend) RPCGasCap() uint64                        { return 10000000 }
@@ -486,6 +490,9 @@ func (b testBackend) RPCEVMTimeout() time.Duration             { return time.Sec
 func (b testBackend) RPCTxFeeCap() float64                     { return 0 }
 func (b testBackend) UnprotectedAllowed() bool                 { return false }
 func (b testBackend) SetHead(number uint64)                    {}
+func (b testBackend) RPCMethodLimits(method string) (uint64, time.Duration) {
+	return b.RPCGasCap(), b.RPCEVMTimeout()
+}
 func (b testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
 	if number == rpc.LatestBlockNumber {
 		return b.chain.CurrentBlock(), nil
@@ -599,6 +606,9 @@ func (b testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (bo
 }
 func (b testBackend) GetPoolTransactions() (types.Transactions, error)         { panic("implement me") }
 func (b testBackend) GetPoolTransaction(txHash common.Hash) *types.Transaction { panic("implement me") }
+func (b testBackend) GetPoolTransactionStatus(txHash common.Hash) txpool.TxStatus {
+	return txpool.TxStatusUnknown
+}
 func (b testBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
 	return 0, nil
 }
@@ -3400,3 +3410,311 @@ func testRPCResponseWithFile(t *testing.T, testid int, result interface{}, rpc s
 func addressToHash(a common.Address) common.Hash {
 	return common.BytesToHash(a.Bytes())
 }
+
+func TestTransactionLifecycleStatus(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend, txHashes = setupReceiptBackend(t, 6)
+		unknown           = common.HexToHash("0xdeadbeef")
+	)
+	// Transaction in block 1 of 6 is buried under 6 blocks
+	if status := newTxStatusTracker(backend, txHashes[0], 12).status(); status == nil || status.Status != TxLifecycleIncluded {
+		t.Fatalf("shallow inclusion status mismatch: have %+v, want %s", status, TxLifecycleIncluded)
+	} else if uint64(*status.Confirmations) != 6 || uint64(*status.BlockNumber) != 1 {
+		t.Fatalf("inclusion details mismatch: have block %d with %d confirmations", *status.BlockNumber, *status.Confirmations)
+	}
+	if status := newTxStatusTracker(backend, txHashes[0], 6).status(); status == nil || status.Status != TxLifecycleConfirmed {
+		t.Fatalf("deep inclusion status mismatch: have %+v, want %s", status, TxLifecycleConfirmed)
+	}
+	// Unknown transactions are only reported dropped if they were seen before
+	tracker := newTxStatusTracker(backend, unknown, 12)
+	if status := tracker.status(); status != nil {
+		t.Fatalf("unknown transaction reported: %+v", status)
+	}
+	tracker.seen = true
+	if status := tracker.status(); status == nil || status.Status != TxLifecycleDropped {
+		t.Fatalf("dropped status mismatch: have %+v, want %s", status, TxLifecycleDropped)
+	}
+	// A failing chain lookup must not be mistaken for a dropped transaction
+	tracker = newTxStatusTracker(&txStatusBackend{testBackend: backend, lookupErr: errors.New("transaction indexing is in progress")}, unknown, 12)
+	tracker.seen = true
+	if status := tracker.status(); status == nil || status.Status != TxLifecycleIndexing {
+		t.Fatalf("indexing status mismatch: have %+v, want %s", status, TxLifecycleIndexing)
+	}
+}
+
+// txStatusBackend is a test backend with a mutable transaction pool and event
+// feeds to drive the transactionStatus subscription.
+type txStatusBackend struct {
+	*testBackend
+	headFeed  event.Feed
+	txFeed    event.Feed
+	lookupErr error
+
+	lock sync.Mutex
+	pool map[common.Hash]*types.Transaction
+}
+
+func (b *txStatusBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.headFeed.Subscribe(ch)
+}
+
+func (b *txStatusBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return b.txFeed.Subscribe(ch)
+}
+
+func (b *txStatusBackend) GetTransaction(ctx context.Context, hash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error) {
+	if b.lookupErr != nil {
+		return false, nil, common.Hash{}, 0, 0, b.lookupErr
+	}
+	return b.testBackend.GetTransaction(ctx, hash)
+}
+
+func (b *txStatusBackend) GetPoolTransaction(hash common.Hash) *types.Transaction {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.pool[hash]
+}
+
+func (b *txStatusBackend) GetPoolTransactionStatus(hash common.Hash) txpool.TxStatus {
+	if b.GetPoolTransaction(hash) != nil {
+		return txpool.TxStatusPending
+	}
+	return txpool.TxStatusUnknown
+}
+
+func (b *txStatusBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var pending []*types.Transaction
+	for _, tx := range b.pool {
+		if from, _ := types.Sender(types.LatestSigner(b.ChainConfig()), tx); from == addr {
+			pending = append(pending, tx)
+		}
+	}
+	return pending, nil
+}
+
+// setPool replaces the content of the pool.
+func (b *txStatusBackend) setPool(txs ...*types.Transaction) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.pool = make(map[common.Hash]*types.Transaction)
+	for _, tx := range txs {
+		b.pool[tx.Hash()] = tx
+	}
+}
+
+// subscribeTransactionStatus subscribes to the lifecycle of a transaction over
+// an in-process RPC connection.
+func subscribeTransactionStatus(t *testing.T, backend Backend, hash common.Hash) chan *TransactionStatusEvent {
+	t.Helper()
+
+	server := rpc.NewServer()
+	t.Cleanup(server.Stop)
+	if err := server.RegisterName("eth", NewTransactionAPI(backend, new(AddrLocker))); err != nil {
+		t.Fatalf("failed to register API: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	t.Cleanup(client.Close)
+
+	events := make(chan *TransactionStatusEvent, 16)
+	sub, err := client.EthSubscribe(context.Background(), events, "transactionStatus", hash)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	t.Cleanup(sub.Unsubscribe)
+	return events
+}
+
+// waitTransactionStatus waits for the next lifecycle update of a subscription.
+func waitTransactionStatus(t *testing.T, events chan *TransactionStatusEvent, want string) {
+	t.Helper()
+
+	select {
+	case ev := <-events:
+		if ev.Status != want {
+			t.Fatalf("status mismatch: have %s, want %s", ev.Status, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for %s status", want)
+	}
+}
+
+// waitTransactionStatusExit waits for the subscription loop to terminate, which
+// releases its subscriptions to the backend feeds.
+func waitTransactionStatusExit(t *testing.T, backend *txStatusBackend) {
+	t.Helper()
+
+	head := core.ChainHeadEvent{}
+	for deadline := time.Now().Add(5 * time.Second); backend.headFeed.Send(head) > 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription loop did not terminate")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTransactionStatusSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		genesis = &core.Genesis{Config: params.TestChainConfig, Alloc: types.GenesisAlloc{}}
+		backend = &txStatusBackend{testBackend: newTestBackend(t, 1, genesis, ethash.NewFaker(), nil)}
+		key, _  = crypto.GenerateKey()
+		signer  = types.LatestSigner(genesis.Config)
+		tx      = types.MustSignNewTx(key, signer, &types.LegacyTx{Nonce: 0, Gas: params.TxGas, GasPrice: big.NewInt(params.InitialBaseFee)})
+		repl    = types.MustSignNewTx(key, signer, &types.LegacyTx{Nonce: 0, Gas: params.TxGas, GasPrice: big.NewInt(2 * params.InitialBaseFee)})
+	)
+	backend.setPool(tx)
+	events := subscribeTransactionStatus(t, backend, tx.Hash())
+	waitTransactionStatus(t, events, TxLifecyclePending)
+
+	// Replace the transaction in the pool, the next head must end the lifecycle
+	backend.setPool(repl)
+	backend.headFeed.Send(core.ChainHeadEvent{})
+	waitTransactionStatus(t, events, TxLifecycleReplaced)
+	waitTransactionStatusExit(t, backend)
+}
+
+func TestTransactionStatusSubscriptionUnseen(t *testing.T) {
+	t.Parallel()
+
+	var (
+		genesis = &core.Genesis{Config: params.TestChainConfig, Alloc: types.GenesisAlloc{}}
+		backend = &txStatusBackend{testBackend: newTestBackend(t, 1, genesis, ethash.NewFaker(), nil)}
+		events  = subscribeTransactionStatus(t, backend, common.HexToHash("0xdeadbeef"))
+	)
+	// The subscription loop subscribes to the heads asynchronously, only count
+	// the delivered ones
+	for sent := 0; sent < unseenTxHeadLimit; {
+		if backend.headFeed.Send(core.ChainHeadEvent{}) > 0 {
+			sent++
+		} else {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitTransactionStatus(t, events, TxLifecycleUnknown)
+	waitTransactionStatusExit(t, backend)
+}
+
+// poolStatusBackend is a test backend with a fixed set of pooled transactions.
+type poolStatusBackend struct {
+	testBackend
+	pool map[common.Hash]txpool.TxStatus
+}
+
+func (b poolStatusBackend) GetPoolTransactionStatus(hash common.Hash) txpool.TxStatus {
+	return b.pool[hash]
+}
+
+func TestTxPoolTransactionStatus(t *testing.T) {
+	t.Parallel()
+
+	var (
+		pending = common.HexToHash("0x01")
+		queued  = common.HexToHash("0x02")
+		api     = NewTxPoolAPI(poolStatusBackend{pool: map[common.Hash]txpool.TxStatus{
+			pending: txpool.TxStatusPending,
+			queued:  txpool.TxStatusQueued,
+		}})
+	)
+	for hash, want := range map[common.Hash]string{
+		pending:                  TxLifecyclePending,
+		queued:                   TxLifecycleQueued,
+		common.HexToHash("0x03"): "unknown",
+	} {
+		if have := api.TransactionStatus(hash); have != want {
+			t.Errorf("status mismatch for %x: have %s, want %s", hash, have, want)
+		}
+	}
+}
+
+// methodLimitsBackend is a test backend with per-method EVM timeouts.
+type methodLimitsBackend struct {
+	*testBackend
+	timeouts map[string]time.Duration
+}
+
+func (b methodLimitsBackend) RPCMethodLimits(method string) (uint64, time.Duration) {
+	return b.RPCGasCap(), b.timeouts[method]
+}
+
+// Tests that the simulation methods abort once their own timeout expires,
+// reporting it instead of returning results of interrupted executions.
+func TestMethodLimitsTimeout(t *testing.T) {
+	t.Parallel()
+
+	var (
+		loop    = common.HexToAddress("0x1000")
+		genesis = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: types.GenesisAlloc{
+				loop: {Balance: big.NewInt(0), Code: hexutil.MustDecode("0x5b600056")}, // JUMPDEST PUSH1 0 JUMP
+			},
+		}
+		backend = methodLimitsBackend{
+			testBackend: newTestBackend(t, 1, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) { b.SetPoS() }),
+			timeouts: map[string]time.Duration{
+				"eth_call":        time.Minute, // Must not be picked up by the others
+				"eth_estimateGas": time.Nanosecond,
+				"eth_simulateV1":  time.Nanosecond,
+			},
+		}
+		api = NewBlockChainAPI(backend)
+	)
+	_, err := api.EstimateGas(context.Background(), TransactionArgs{To: &loop}, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "gas estimation aborted (timeout = 1ns)") {
+		t.Errorf("estimateGas error mismatch: have %v", err)
+	}
+	// Depending on timing, the simulation is aborted either before or during
+	// the execution of the call
+	_, err = api.SimulateV1(context.Background(), simOpts{BlockStateCalls: []simBlock{{Calls: []TransactionArgs{{To: &loop}}}}}, nil)
+	if err == nil || (!errors.Is(err, context.DeadlineExceeded) && !strings.Contains(err.Error(), "execution aborted (timeout = 1ns)")) {
+		t.Errorf("simulateV1 error mismatch: have %v", err)
+	}
+}
+
+// prunedStateBackend is a test backend whose historical state lookups fail
+// with the configured error.
+type prunedStateBackend struct {
+	*testBackend
+	err error
+}
+
+func (b prunedStateBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	return nil, nil, b.err
+}
+
+func TestGetProofPrunedState(t *testing.T) {
+	t.Parallel()
+
+	var (
+		genesis = &core.Genesis{Config: params.MergedTestChainConfig, Alloc: types.GenesisAlloc{}}
+		backend = newTestBackend(t, 2, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) { b.SetPoS() })
+		missing = &trie.MissingNodeError{NodeHash: common.HexToHash("0x01")}
+		api     = NewBlockChainAPI(prunedStateBackend{backend, fmt.Errorf("wrapped: %w", missing)})
+	)
+	_, err := api.GetProof(context.Background(), common.Address{}, nil, rpc.BlockNumberOrHashWithNumber(1))
+	var perr *stateUnavailableError
+	if !errors.As(err, &perr) {
+		t.Fatalf("error mismatch: have %v, want state unavailable", err)
+	}
+	if perr.ErrorCode() != errCodeStateUnavailable {
+		t.Fatalf("error code mismatch: have %d, want %d", perr.ErrorCode(), errCodeStateUnavailable)
+	}
+	// Requesting a block that doesn't exist must not be reported as pruned
+	_, err = api.GetProof(context.Background(), common.Address{}, nil, rpc.BlockNumberOrHashWithNumber(10))
+	if errors.As(err, &perr) {
+		t.Fatalf("missing block reported as pruned state: %v", err)
+	}
+	// Failures other than missing state must be passed through as is
+	failure := errors.New("database closed")
+	api = NewBlockChainAPI(prunedStateBackend{backend, failure})
+	if _, err = api.GetProof(context.Background(), common.Address{}, nil, rpc.BlockNumberOrHashWithNumber(1)); err != failure {
+		t.Fatalf("error mismatch: have %v, want %v", err, failure)
+	}
+}
@@ -108,6 +108,7 @@ const (
 	errCodeInvalidParams           = -32602
 	errCodeReverted                = -32000
 	errCodeVMError                 = -32015
+	errCodeStateUnavailable        = -32000
 )
 
 func txValidationError(err error) *invalidTxError {
@@ -168,3 +169,10 @@ type blockGasLimitReachedError struct{ message string }
 
 func (e *blockGasLimitReachedError) Error() string  { return e.message }
 func (e *blockGasLimitReachedError) ErrorCode() int { return errCodeBlockGasLimitReached }
+
+// stateUnavailableError is returned when the state of an existing block can't
+// be accessed, most likely because it was pruned.
+type stateUnavailableError struct{ message string }
+
+func (e *stateUnavailableError) Error() string  { return e.message }
+func (e *stateUnavailableError) ErrorCode() int { return errCodeStateUnavailable }
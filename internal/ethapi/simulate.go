@@ -92,6 +92,7 @@ type simulator struct {
 	traceTransfers bool
 	validate       bool
 	fullTx         bool
+	timeout        time.Duration
 }
 
 // execute runs the simulation of a series of blocks.
@@ -101,7 +102,7 @@ func (sim *simulator) execute(ctx context.Context, blocks []simBlock) ([]map[str
 	}
 	var (
 		cancel  context.CancelFunc
-		timeout = sim.b.RPCEVMTimeout()
+		timeout = sim.timeout
 	)
 	if timeout > 0 {
 		ctx, cancel = context.WithTimeout(ctx, timeout)
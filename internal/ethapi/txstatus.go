@@ -0,0 +1,261 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// defaultConfirmationDepth is the number of blocks (including the inclusion
+// block) after which a transaction is reported confirmed, unless requested
+// otherwise by the subscriber.
+const defaultConfirmationDepth = 12
+
+// unseenTxHeadLimit is the number of new heads a transaction neither in the
+// pool nor in the chain is waited for, before the subscription is ended with
+// the unknown status. It leaves room to subscribe ahead of the submission.
+const unseenTxHeadLimit = 32
+
+// Lifecycle stages of a transaction reported by the transactionStatus
+// subscription.
+const (
+	TxLifecycleQueued    = "queued"    // in the pool, but not yet executable
+	TxLifecyclePending   = "pending"   // in the pool and executable
+	TxLifecycleIncluded  = "included"  // included in a canonical block
+	TxLifecycleConfirmed = "confirmed" // included and buried under enough blocks
+	TxLifecycleReplaced  = "replaced"  // superseded by another transaction with the same nonce
+	TxLifecycleDropped   = "dropped"   // evicted from the pool
+	TxLifecycleIndexing  = "indexing"  // chain lookup unavailable until the transaction indexing completes
+	TxLifecycleUnknown   = "unknown"   // never seen in the pool nor in the chain
+)
+
+// TransactionStatusEvent is a lifecycle update of a single transaction.
+type TransactionStatusEvent struct {
+	Hash          common.Hash     `json:"hash"`
+	Status        string          `json:"status"`
+	BlockHash     *common.Hash    `json:"blockHash,omitempty"`
+	BlockNumber   *hexutil.Uint64 `json:"blockNumber,omitempty"`
+	Index         *hexutil.Uint64 `json:"transactionIndex,omitempty"`
+	Confirmations *hexutil.Uint64 `json:"confirmations,omitempty"`
+}
+
+// same reports whether two updates describe the same lifecycle stage, ignoring
+// the confirmation count which changes with every new head.
+func (ev *TransactionStatusEvent) same(other *TransactionStatusEvent) bool {
+	if other == nil || ev.Status != other.Status {
+		return false
+	}
+	if ev.BlockHash == nil || other.BlockHash == nil {
+		return ev.BlockHash == other.BlockHash
+	}
+	return *ev.BlockHash == *other.BlockHash
+}
+
+// final reports whether the update ends the lifecycle of the transaction.
+func (ev *TransactionStatusEvent) final() bool {
+	switch ev.Status {
+	case TxLifecycleConfirmed, TxLifecycleReplaced, TxLifecycleDropped, TxLifecycleUnknown:
+		return true
+	}
+	return false
+}
+
+// TransactionStatus creates a subscription streaming the lifecycle updates of a
+// transaction: queued, pending, included in a block, confirmed at the requested
+// depth, replaced or dropped. A reorg moving the transaction back into the pool
+// or into a different block is reported as a new update. While the transaction
+// indexing is in progress, transactions missing from the pool are reported as
+// indexing instead of dropped. The subscription ends once the transaction is
+// confirmed, replaced or dropped, or if it is not seen for unseenTxHeadLimit
+// blocks.
+func (api *TransactionAPI) TransactionStatus(ctx context.Context, hash common.Hash, depth *hexutil.Uint64) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	confirmations := uint64(defaultConfirmationDepth)
+	if depth != nil && *depth > 0 {
+		confirmations = uint64(*depth)
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		var (
+			heads   = make(chan core.ChainHeadEvent, 16)
+			txs     = make(chan core.NewTxsEvent, 128)
+			headSub = api.b.SubscribeChainHeadEvent(heads)
+			txSub   = api.b.SubscribeNewTxsEvent(txs)
+			tracker = newTxStatusTracker(api.b, hash, confirmations)
+			unseen  int
+		)
+		defer headSub.Unsubscribe()
+		defer txSub.Unsubscribe()
+
+		// update checks the current status of the transaction, notifies the
+		// subscriber on changes and reports whether the lifecycle is over.
+		update := func() bool {
+			status := tracker.status()
+			if status == nil || status.same(tracker.last) {
+				return false
+			}
+			notifier.Notify(rpcSub.ID, status)
+			tracker.last = status
+			return status.final()
+		}
+		if update() {
+			return
+		}
+		for {
+			select {
+			case <-heads:
+				if update() {
+					return
+				}
+				// Give up on transactions never showing up, unless the chain
+				// lookup was inconclusive due to the indexing in progress
+				if !tracker.seen && !tracker.indexing {
+					if unseen++; unseen >= unseenTxHeadLimit {
+						notifier.Notify(rpcSub.ID, &TransactionStatusEvent{Hash: hash, Status: TxLifecycleUnknown})
+						return
+					}
+				}
+			case ev := <-txs:
+				for _, tx := range ev.Txs {
+					if tx.Hash() == hash {
+						if update() {
+							return
+						}
+						break
+					}
+				}
+			case <-headSub.Err():
+				return
+			case <-txSub.Err():
+				return
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// txStatusTracker resolves the lifecycle stage of a single transaction across
+// repeated lookups, remembering the transaction once seen to be able to tell
+// replaced transactions apart from dropped ones.
+type txStatusTracker struct {
+	b     Backend
+	hash  common.Hash
+	depth uint64
+
+	tx       *types.Transaction      // Transaction body, once seen
+	seen     bool                    // Whether the transaction was seen in the pool or the chain
+	indexing bool                    // Whether the last chain lookup failed due to the indexing
+	last     *TransactionStatusEvent // Last status reported to the subscriber
+}
+
+func newTxStatusTracker(b Backend, hash common.Hash, depth uint64) *txStatusTracker {
+	return &txStatusTracker{b: b, hash: hash, depth: depth}
+}
+
+// status resolves the current lifecycle stage of the transaction. Nil is
+// returned if the transaction was never seen and is still unknown.
+func (t *txStatusTracker) status() *TransactionStatusEvent {
+	found, tx, blockHash, blockNumber, index, err := t.b.GetTransaction(context.Background(), t.hash)
+	t.indexing = err != nil
+	if err == nil && found && tx != nil {
+		t.tx, t.seen = tx, true
+
+		var (
+			head     = t.b.CurrentHeader().Number.Uint64()
+			confirms uint64
+		)
+		if head >= blockNumber {
+			confirms = head - blockNumber + 1
+		}
+		status := TxLifecycleIncluded
+		if confirms >= t.depth {
+			status = TxLifecycleConfirmed
+		}
+		return &TransactionStatusEvent{
+			Hash:          t.hash,
+			Status:        status,
+			BlockHash:     &blockHash,
+			BlockNumber:   (*hexutil.Uint64)(&blockNumber),
+			Index:         (*hexutil.Uint64)(&index),
+			Confirmations: (*hexutil.Uint64)(&confirms),
+		}
+	}
+	var status string
+	switch t.b.GetPoolTransactionStatus(t.hash) {
+	case txpool.TxStatusPending:
+		status = TxLifecyclePending
+	case txpool.TxStatusQueued:
+		status = TxLifecycleQueued
+	}
+	if status != "" {
+		if t.tx == nil {
+			t.tx = t.b.GetPoolTransaction(t.hash)
+		}
+		t.seen = true
+		return &TransactionStatusEvent{Hash: t.hash, Status: status}
+	}
+	// The transaction is neither in the pool nor (as far as known) in the chain.
+	// If the chain lookup failed, e.g. because the transaction indexing is still
+	// in progress, it might well be included, so don't report it dropped.
+	if t.indexing {
+		return &TransactionStatusEvent{Hash: t.hash, Status: TxLifecycleIndexing}
+	}
+	if !t.seen {
+		return nil
+	}
+	if t.replaced() {
+		return &TransactionStatusEvent{Hash: t.hash, Status: TxLifecycleReplaced}
+	}
+	return &TransactionStatusEvent{Hash: t.hash, Status: TxLifecycleDropped}
+}
+
+// replaced reports whether the nonce of the transaction, which is gone from the
+// pool, was taken by another transaction, either included or still pooled.
+func (t *txStatusTracker) replaced() bool {
+	if t.tx == nil {
+		return false
+	}
+	from, err := types.Sender(types.LatestSigner(t.b.ChainConfig()), t.tx)
+	if err != nil {
+		return false
+	}
+	if state, _, err := t.b.StateAndHeaderByNumber(context.Background(), rpc.LatestBlockNumber); err == nil && state != nil {
+		if state.GetNonce(from) > t.tx.Nonce() {
+			return true
+		}
+	}
+	pending, queued := t.b.TxPoolContentFrom(from)
+	for _, tx := range append(pending, queued...) {
+		if tx.Nonce() == t.tx.Nonce() && tx.Hash() != t.hash {
+			return true
+		}
+	}
+	return false
+}
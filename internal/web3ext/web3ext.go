@@ -165,6 +165,10 @@ web3._extend({
 			name: 'nodeInfo',
 			getter: 'admin_nodeInfo'
 		}),
+		new web3._extend.Property({
+			name: 'nodeInfoExtended',
+			getter: 'admin_nodeInfoExtended'
+		}),
 		new web3._extend.Property({
 			name: 'peers',
 			getter: 'admin_peers'
@@ -238,6 +242,20 @@ web3._extend({
 			name: 'chaindbCompact',
 			call: 'debug_chaindbCompact',
 		}),
+		new web3._extend.Method({
+			name: 'compactDatabase',
+			call: 'debug_compactDatabase',
+			params: 2,
+			inputFormatter: [null, null],
+		}),
+		new web3._extend.Method({
+			name: 'compactionProgress',
+			call: 'debug_compactionProgress',
+		}),
+		new web3._extend.Method({
+			name: 'caches',
+			call: 'debug_caches',
+		}),
 		new web3._extend.Method({
 			name: 'verbosity',
 			call: 'debug_verbosity',
@@ -730,7 +748,14 @@ web3._extend({
 const TxpoolJs = `
 web3._extend({
 	property: 'txpool',
-	methods: [],
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'transactionStatus',
+			call: 'txpool_transactionStatus',
+			params: 1,
+		}),
+	],
 	properties:
 	[
 		new web3._extend.Property({
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -64,6 +65,17 @@ type Config struct {
 	// in memory.
 	DataDir string
 
+	// DataDirOverrides relocates individual stores of the instance directory (e.g.
+	// "chaindata" or "nodes") to custom locations, for example to place the chain
+	// database on a separate disk. Relative locations are resolved against DataDir.
+	DataDirOverrides map[string]string `toml:",omitempty"`
+
+	// ShutdownTimeout is the deadline for each registered service to stop. When a
+	// service exceeds it, the goroutines are dumped to stderr. Services owning
+	// databases are waited for regardless, any other one is skipped and the
+	// shutdown carries on with the remaining services. Zero waits indefinitely.
+	ShutdownTimeout time.Duration `toml:",omitempty"`
+
 	// Configuration of peer-to-peer networking.
 	P2P p2p.Config
 
@@ -340,6 +352,13 @@ func (c *Config) ResolvePath(path string) string {
 	if c.DataDir == "" {
 		return ""
 	}
+	// Stores explicitly relocated by the user take precedence over the layout
+	if override, ok := c.DataDirOverrides[path]; ok && override != "" {
+		if filepath.IsAbs(override) {
+			return override
+		}
+		return filepath.Join(c.DataDir, override)
+	}
 	// Backwards-compatibility: ensure that data directory files created
 	// by geth 1.4 are used if they exist.
 	if warn, isOld := isOldGethResource[path]; isOld {
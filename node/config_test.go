@@ -100,6 +100,36 @@ func TestIPCPathResolution(t *testing.T) {
 	}
 }
 
+// Tests that relocated stores are resolved to their overridden locations while
+// everything else stays within the instance directory.
+func TestDataDirOverrides(t *testing.T) {
+	var (
+		datadir = t.TempDir()
+		fastdir = filepath.Join(t.TempDir(), "chaindata")
+	)
+	config := &Config{
+		Name:    "geth",
+		DataDir: datadir,
+		DataDirOverrides: map[string]string{
+			"chaindata": fastdir,
+			"nodes":     "nodes-relocated",
+		},
+	}
+	var tests = []struct {
+		path string
+		want string
+	}{
+		{"chaindata", fastdir},
+		{"nodes", filepath.Join(datadir, "nodes-relocated")},
+		{"nodekey", filepath.Join(datadir, "geth", "nodekey")},
+	}
+	for i, test := range tests {
+		if have := config.ResolvePath(test.path); have != test.want {
+			t.Errorf("test %d: path mismatch: have %s, want %s", i, have, test.want)
+		}
+	}
+}
+
 // Tests that node keys can be correctly created, persisted, loaded and/or made
 // ephemeral.
 func TestNodeKeyPersistency(t *testing.T) {
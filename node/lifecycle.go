@@ -29,3 +29,48 @@ type Lifecycle interface {
 	// are all terminated.
 	Stop() error
 }
+
+// DatabaseOwner is an optional interface for lifecycles holding database handles
+// opened through the node. Database owners are stopped in StopPhaseChain, after
+// every other lifecycle. The shutdown deadline never abandons an owner failing
+// to stop in time, since closing the databases underneath its goroutines could
+// corrupt them; the node keeps waiting for it instead.
+type DatabaseOwner interface {
+	// OwnsDatabases reports whether the lifecycle currently holds open databases.
+	OwnsDatabases() bool
+}
+
+// StopPhase is the stage of the node shutdown in which a lifecycle is stopped.
+// Phases are run in increasing order, after the RPC endpoints are closed.
+type StopPhase int
+
+const (
+	// StopPhaseProducer is for lifecycles driving block production or import,
+	// which must stop before the services consuming the chain.
+	StopPhaseProducer StopPhase = iota
+
+	// StopPhaseService is the default phase of lifecycles.
+	StopPhaseService
+
+	// StopPhaseChain is for lifecycles owning the chain and its databases, which
+	// are stopped last so nothing writes to them while they flush.
+	StopPhaseChain
+)
+
+// StopPhaser is an optional interface for lifecycles which need to be stopped
+// in a phase other than the default one.
+type StopPhaser interface {
+	// StopPhase returns the shutdown phase of the lifecycle.
+	StopPhase() StopPhase
+}
+
+// lifecycleStopPhase returns the shutdown phase of the given lifecycle.
+func lifecycleStopPhase(lifecycle Lifecycle) StopPhase {
+	if phaser, ok := lifecycle.(StopPhaser); ok {
+		return phaser.StopPhase()
+	}
+	if owner, ok := lifecycle.(DatabaseOwner); ok && owner.OwnsDatabases() {
+		return StopPhaseChain
+	}
+	return StopPhaseService
+}
@@ -17,17 +17,21 @@
 package node
 
 import (
+	"cmp"
 	crand "crypto/rand"
 	"errors"
 	"fmt"
 	"hash/crc32"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
+	"runtime/pprof"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
@@ -42,6 +46,14 @@ import (
 	"github.com/gofrs/flock"
 )
 
+// stopReportInterval is the interval at which services taking long to stop are
+// reported during shutdown.
+const stopReportInterval = 8 * time.Second
+
+// stopDumpOutput is where the goroutines are dumped when a service fails to stop
+// before the shutdown deadline.
+var stopDumpOutput io.Writer = os.Stderr
+
 // Node is a container on which services can be registered.
 type Node struct {
 	eventmux      *event.TypeMux
@@ -282,14 +294,24 @@ func (n *Node) openEndpoints() error {
 
 // stopServices terminates running services, RPC and p2p networking.
 // It is the inverse of Start.
+//
+// The RPC endpoints are stopped first so no new requests reach the services,
+// then the lifecycles are stopped phase by phase in dependency order, block
+// producers first and the owners of the chain databases last. Within a phase,
+// lifecycles are stopped in reverse registration order. A lifecycle missing
+// the shutdown deadline is skipped, the remaining ones are still stopped.
 func (n *Node) stopServices(running []Lifecycle) error {
 	n.stopRPC()
 
-	// Stop running lifecycles in reverse order.
+	ordered := slices.Clone(running)
+	slices.Reverse(ordered)
+	slices.SortStableFunc(ordered, func(a, b Lifecycle) int {
+		return cmp.Compare(lifecycleStopPhase(a), lifecycleStopPhase(b))
+	})
 	failure := &StopError{Services: make(map[reflect.Type]error)}
-	for i := len(running) - 1; i >= 0; i-- {
-		if err := running[i].Stop(); err != nil {
-			failure.Services[reflect.TypeOf(running[i])] = err
+	for _, lifecycle := range ordered {
+		if err := n.stopLifecycle(lifecycle); err != nil {
+			failure.Services[reflect.TypeOf(lifecycle)] = err
 		}
 	}
 
@@ -302,6 +324,51 @@ func (n *Node) stopServices(running []Lifecycle) error {
 	return nil
 }
 
+// stopLifecycle stops a single lifecycle, periodically reporting it while it is
+// taking long to stop. If a shutdown deadline is configured and the lifecycle
+// fails to stop before it, the goroutines are dumped. Lifecycles owning database
+// handles are waited for regardless, since closing the databases underneath them
+// could lose data they still have to flush. Any other lifecycle is abandoned
+// with ErrStopTimeout, letting the shutdown proceed to the database owners.
+func (n *Node) stopLifecycle(lifecycle Lifecycle) error {
+	done := make(chan error, 1)
+	go func() { done <- lifecycle.Stop() }()
+
+	var (
+		start    = time.Now()
+		report   = time.NewTicker(stopReportInterval)
+		deadline <-chan time.Time
+	)
+	defer report.Stop()
+
+	if n.config.ShutdownTimeout > 0 {
+		timer := time.NewTimer(n.config.ShutdownTimeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	for {
+		select {
+		case err := <-done:
+			return err
+
+		case <-report.C:
+			n.log.Warn("Service is taking long to stop", "service", fmt.Sprintf("%T", lifecycle), "elapsed", common.PrettyDuration(time.Since(start)))
+
+		case <-deadline:
+			n.log.Error("Service failed to stop before deadline, dumping goroutines", "service", fmt.Sprintf("%T", lifecycle), "elapsed", common.PrettyDuration(time.Since(start)))
+			pprof.Lookup("goroutine").WriteTo(stopDumpOutput, 2)
+
+			if owner, ok := lifecycle.(DatabaseOwner); ok && owner.OwnsDatabases() {
+				n.log.Error("Waiting for service owning databases to stop", "service", fmt.Sprintf("%T", lifecycle))
+				deadline = nil
+				continue
+			}
+			n.log.Error("Skipping service stuck in shutdown", "service", fmt.Sprintf("%T", lifecycle))
+			return ErrStopTimeout
+		}
+	}
+}
+
 func (n *Node) openDataDir() error {
 	if n.config.DataDir == "" {
 		return nil // ephemeral
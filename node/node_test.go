@@ -17,6 +17,7 @@
 package node
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -25,7 +26,9 @@ import (
 	"reflect"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -384,6 +387,125 @@ func TestLifecycleTerminationGuarantee(t *testing.T) {
 	stack.server.PrivateKey = testNodeKey
 }
 
+// setStopDumpOutput replaces the goroutine dump output used when a lifecycle
+// exceeds the shutdown deadline for the duration of a test.
+func setStopDumpOutput(t *testing.T) *bytes.Buffer {
+	dump, old := new(bytes.Buffer), stopDumpOutput
+	stopDumpOutput = dump
+	t.Cleanup(func() { stopDumpOutput = old })
+
+	return dump
+}
+
+// dbOwnerService is a lifecycle declaring that it owns database handles.
+type dbOwnerService struct {
+	InstrumentedService
+}
+
+func (s *dbOwnerService) OwnsDatabases() bool { return true }
+
+// producerService is a lifecycle declaring that it drives block production.
+type producerService struct {
+	InstrumentedService
+}
+
+func (s *producerService) StopPhase() StopPhase { return StopPhaseProducer }
+
+// Tests that lifecycles are stopped phase by phase: block producers first, the
+// database owners last and anything else in reverse registration order.
+func TestLifecycleStopPhases(t *testing.T) {
+	stack, err := New(testNodeConfig())
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	var stopped []string
+	record := func(name string) func() {
+		return func() { stopped = append(stopped, name) }
+	}
+	stack.RegisterLifecycle(&producerService{InstrumentedService{stopHook: record("producer")}})
+	stack.RegisterLifecycle(&dbOwnerService{InstrumentedService{stopHook: record("chain")}})
+	stack.RegisterLifecycle(&InstrumentedService{stopHook: record("first")})
+	stack.RegisterLifecycle(&InstrumentedService{stopHook: record("second")})
+
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	if err := stack.Close(); err != nil {
+		t.Fatalf("failed to close protocol stack: %v", err)
+	}
+	if want := []string{"producer", "second", "first", "chain"}; !slices.Equal(stopped, want) {
+		t.Fatalf("stop order mismatch: have %v, want %v", stopped, want)
+	}
+}
+
+// Tests that a lifecycle failing to stop before the shutdown deadline gets its
+// goroutines dumped and is skipped, with the remaining lifecycles, including
+// the database owners, still being stopped.
+func TestLifecycleStopDeadline(t *testing.T) {
+	dump := setStopDumpOutput(t)
+
+	config := testNodeConfig()
+	config.ShutdownTimeout = 50 * time.Millisecond
+
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	release := make(chan struct{})
+	defer close(release)
+
+	var ownerStopped atomic.Bool
+	stack.RegisterLifecycle(&dbOwnerService{InstrumentedService{stopHook: func() { ownerStopped.Store(true) }}})
+	stack.RegisterLifecycle(&InstrumentedService{stopHook: func() { <-release }})
+
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	err = stack.Close()
+	if err, ok := err.(*StopError); !ok {
+		t.Fatalf("termination failure mismatch: have %v, want StopError", err)
+	} else if have := err.Services[reflect.TypeOf(&InstrumentedService{})]; have != ErrStopTimeout {
+		t.Fatalf("stuck service failure mismatch: have %v, want %v", have, ErrStopTimeout)
+	}
+	if !ownerStopped.Load() {
+		t.Fatalf("database owner not stopped after a stuck service")
+	}
+	if !strings.Contains(dump.String(), "goroutine") {
+		t.Fatalf("goroutines not dumped: %q", dump.String())
+	}
+}
+
+// Tests that a lifecycle owning databases is waited for past the shutdown
+// deadline, instead of closing the databases underneath it.
+func TestLifecycleStopDeadlineDatabaseOwner(t *testing.T) {
+	dump := setStopDumpOutput(t)
+
+	config := testNodeConfig()
+	config.ShutdownTimeout = 50 * time.Millisecond
+
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	var stopped atomic.Bool
+	stack.RegisterLifecycle(&dbOwnerService{InstrumentedService{stopHook: func() {
+		time.Sleep(4 * config.ShutdownTimeout)
+		stopped.Store(true)
+	}}})
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	if err := stack.Close(); err != nil {
+		t.Fatalf("failed to close protocol stack: %v", err)
+	}
+	if !stopped.Load() {
+		t.Fatalf("shutdown continued before the database owner stopped")
+	}
+	if dump.Len() == 0 {
+		t.Fatalf("goroutines not dumped after the shutdown deadline")
+	}
+}
+
 // Tests whether a handler can be successfully mounted on the canonical HTTP server
 // on the given prefix
 func TestRegisterHandler_Successful(t *testing.T) {
@@ -96,6 +96,7 @@ type PeerEvent struct {
 	Peer          enode.ID      `json:"peer"`
 	Error         string        `json:"error,omitempty"`
 	Protocol      string        `json:"protocol,omitempty"`
+	Protocols     []string      `json:"protocols,omitempty"`
 	MsgCode       *uint64       `json:"msg_code,omitempty"`
 	MsgSize       *uint32       `json:"msg_size,omitempty"`
 	LocalAddress  string        `json:"local,omitempty"`
@@ -191,6 +192,17 @@ func (p *Peer) RunningCap(protocol string, versions []uint) bool {
 	return false
 }
 
+// runningProtocols returns the sorted list of protocols (name/version) the peer
+// is actively running with this node.
+func (p *Peer) runningProtocols() []string {
+	protos := make([]string, 0, len(p.running))
+	for _, proto := range p.running {
+		protos = append(protos, Cap{Name: proto.Name, Version: proto.Version}.String())
+	}
+	slices.Sort(protos)
+	return protos
+}
+
 // RemoteAddr returns the remote address of the network connection.
 func (p *Peer) RemoteAddr() net.Addr {
 	return p.rw.fd.RemoteAddr()
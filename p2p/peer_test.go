@@ -266,6 +266,19 @@ func TestNewPeer(t *testing.T) {
 	p.Disconnect(DiscAlreadyConnected) // Should not hang
 }
 
+func TestPeerRunningProtocols(t *testing.T) {
+	protos := []Protocol{
+		{Name: "b", Version: 2, Length: 1, Run: func(*Peer, MsgReadWriter) error { return nil }},
+		{Name: "a", Version: 1, Length: 1, Run: func(*Peer, MsgReadWriter) error { return nil }},
+	}
+	closer, _, peer, _ := testPeer(protos)
+	defer closer()
+
+	if have, want := peer.runningProtocols(), []string{"a/1", "b/2"}; !reflect.DeepEqual(have, want) {
+		t.Fatalf("running protocols mismatch: have %v, want %v", have, want)
+	}
+}
+
 func TestMatchProtocols(t *testing.T) {
 	tests := []struct {
 		Remote []Cap
@@ -1062,6 +1062,7 @@ func (srv *Server) runPeer(p *Peer) {
 	srv.peerFeed.Send(&PeerEvent{
 		Type:          PeerEventTypeAdd,
 		Peer:          p.ID(),
+		Protocols:     p.runningProtocols(),
 		RemoteAddress: p.RemoteAddr().String(),
 		LocalAddress:  p.LocalAddr().String(),
 	})
@@ -1082,6 +1083,7 @@ func (srv *Server) runPeer(p *Peer) {
 		Type:          PeerEventTypeDrop,
 		Peer:          p.ID(),
 		Error:         err.Error(),
+		Protocols:     p.runningProtocols(),
 		RemoteAddress: p.RemoteAddr().String(),
 		LocalAddress:  p.LocalAddr().String(),
 	})
@@ -19,6 +19,7 @@ package params
 import (
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params/forks"
@@ -321,6 +322,18 @@ type ChainConfig struct {
 	GrayGlacierBlock    *big.Int `json:"grayGlacierBlock,omitempty"`    // Eip-5133 (bomb delay) switch block (nil = no fork, 0 = already activated)
 	MergeNetsplitBlock  *big.Int `json:"mergeNetsplitBlock,omitempty"`  // Virtual fork after The Merge to use as a network splitter
 
+	// EIP2200Block and EIP2929Block activate single EIPs ahead of the fork that
+	// bundles them (Istanbul and Berlin respectively), allowing networks to roll
+	// them out in a different order than mainnet. Leaving them unset activates
+	// the EIPs together with their fork.
+	EIP2200Block *big.Int `json:"eip2200Block,omitempty"` // EIP-2200 (SSTORE net gas metering) early switch block (nil = with Istanbul)
+	EIP2929Block *big.Int `json:"eip2929Block,omitempty"` // EIP-2929 (state access gas costs) early switch block (nil = with Berlin)
+
+	// BMTVerifyBlock enables the binary Merkle tree inclusion proof verification
+	// precompile. It is specific to this client and may be scheduled independently
+	// of the upstream forks.
+	BMTVerifyBlock *big.Int `json:"bmtVerifyBlock,omitempty"` // BMT verification precompile switch block (nil = no fork, 0 = already activated)
+
 	// Fork scheduling was switched from blocks to timestamps here
 
 	ShanghaiTime *uint64 `json:"shanghaiTime,omitempty"` // Shanghai switch time (nil = no fork, 0 = already on shanghai)
@@ -423,6 +436,15 @@ func (c *ChainConfig) Description() string {
 	if c.GrayGlacierBlock != nil {
 		banner += fmt.Sprintf(" - Gray Glacier:                #%-8v (https://github.com/ethereum/execution-specs/blob/master/network-upgrades/mainnet-upgrades/gray-glacier.md)\n", c.GrayGlacierBlock)
 	}
+	if c.EIP2200Block != nil {
+		banner += fmt.Sprintf(" - EIP-2200 (early activation): #%-8v\n", c.EIP2200Block)
+	}
+	if c.EIP2929Block != nil {
+		banner += fmt.Sprintf(" - EIP-2929 (early activation): #%-8v\n", c.EIP2929Block)
+	}
+	if c.BMTVerifyBlock != nil {
+		banner += fmt.Sprintf(" - BMT verification precompile: #%-8v\n", c.BMTVerifyBlock)
+	}
 	banner += "\n"
 
 	// Add a special section for the merge as it's non-obvious
@@ -519,6 +541,24 @@ func (c *ChainConfig) IsLondon(num *big.Int) bool {
 	return isBlockForked(c.LondonBlock, num)
 }
 
+// IsEIP2200 returns whether EIP-2200 is active at num, either through the
+// Istanbul fork or its early activation block.
+func (c *ChainConfig) IsEIP2200(num *big.Int) bool {
+	return c.IsIstanbul(num) || isBlockForked(c.EIP2200Block, num)
+}
+
+// IsEIP2929 returns whether EIP-2929 is active at num, either through the
+// Berlin fork or its early activation block.
+func (c *ChainConfig) IsEIP2929(num *big.Int) bool {
+	return c.IsBerlin(num) || isBlockForked(c.EIP2929Block, num)
+}
+
+// IsBMTVerify returns whether num is either equal to the BMT verification
+// precompile fork block or greater.
+func (c *ChainConfig) IsBMTVerify(num *big.Int) bool {
+	return isBlockForked(c.BMTVerifyBlock, num)
+}
+
 // IsArrowGlacier returns whether num is either equal to the Arrow Glacier (EIP-4345) fork block or greater.
 func (c *ChainConfig) IsArrowGlacier(num *big.Int) bool {
 	return isBlockForked(c.ArrowGlacierBlock, num)
@@ -652,9 +692,165 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	return c.checkEIPOverrideOrder()
+}
+
+// checkEIPOverrideOrder checks that the EIPs activated ahead of their bundling
+// fork are scheduled consistently: before that fork, and not before the forks
+// and EIPs they build upon.
+func (c *ChainConfig) checkEIPOverrideOrder() error {
+	for _, eip := range []struct {
+		name   string
+		block  *big.Int
+		fork   string   // Name of the fork bundling the EIP
+		before *big.Int // Block of the fork bundling the EIP
+		after  string   // Name of the fork or EIP the EIP builds upon
+		active func(*big.Int) bool
+	}{
+		{name: "eip2200Block", block: c.EIP2200Block, fork: "istanbulBlock", before: c.IstanbulBlock, after: "petersburgBlock", active: c.IsPetersburg},
+		{name: "eip2929Block", block: c.EIP2929Block, fork: "berlinBlock", before: c.BerlinBlock, after: "eip2200Block", active: c.IsEIP2200},
+	} {
+		if eip.block == nil {
+			continue
+		}
+		if eip.before != nil && eip.block.Cmp(eip.before) >= 0 {
+			return fmt.Errorf("unsupported fork ordering: %v enabled at block %v, not before %v at block %v",
+				eip.name, eip.block, eip.fork, eip.before)
+		}
+		if !eip.active(eip.block) {
+			return fmt.Errorf("unsupported fork ordering: %v enabled at block %v, but %v not enabled by then",
+				eip.name, eip.block, eip.after)
+		}
+	}
 	return nil
 }
 
+// ForkActivation describes when a single fork is scheduled to be activated.
+// Forks up to and including the merge are block based, later ones are time
+// based, so exactly one of Block and Timestamp is set.
+type ForkActivation struct {
+	Name      string   `json:"name"`
+	Block     *big.Int `json:"block,omitempty"`
+	Timestamp *uint64  `json:"timestamp,omitempty"`
+}
+
+// ScheduledForks returns the forks enabled in the chain config in activation
+// order, named after their configuration fields. Forks which are not scheduled
+// are omitted.
+func (c *ChainConfig) ScheduledForks() []ForkActivation {
+	var forks []ForkActivation
+	for _, fork := range []ForkActivation{
+		{Name: "homesteadBlock", Block: c.HomesteadBlock},
+		{Name: "daoForkBlock", Block: c.DAOForkBlock},
+		{Name: "eip150Block", Block: c.EIP150Block},
+		{Name: "eip155Block", Block: c.EIP155Block},
+		{Name: "eip158Block", Block: c.EIP158Block},
+		{Name: "byzantiumBlock", Block: c.ByzantiumBlock},
+		{Name: "constantinopleBlock", Block: c.ConstantinopleBlock},
+		{Name: "petersburgBlock", Block: c.PetersburgBlock},
+		{Name: "istanbulBlock", Block: c.IstanbulBlock},
+		{Name: "muirGlacierBlock", Block: c.MuirGlacierBlock},
+		{Name: "berlinBlock", Block: c.BerlinBlock},
+		{Name: "londonBlock", Block: c.LondonBlock},
+		{Name: "arrowGlacierBlock", Block: c.ArrowGlacierBlock},
+		{Name: "grayGlacierBlock", Block: c.GrayGlacierBlock},
+		{Name: "mergeNetsplitBlock", Block: c.MergeNetsplitBlock},
+		{Name: "eip2200Block", Block: c.EIP2200Block},
+		{Name: "eip2929Block", Block: c.EIP2929Block},
+		{Name: "bmtVerifyBlock", Block: c.BMTVerifyBlock},
+		{Name: "shanghaiTime", Timestamp: c.ShanghaiTime},
+		{Name: "cancunTime", Timestamp: c.CancunTime},
+		{Name: "pragueTime", Timestamp: c.PragueTime},
+		{Name: "verkleTime", Timestamp: c.VerkleTime},
+	} {
+		if fork.Block != nil || fork.Timestamp != nil {
+			forks = append(forks, fork)
+		}
+	}
+	return forks
+}
+
+// forkEIPs lists the EIPs enabled and disabled by each fork, keyed by the
+// configuration field name reported by ScheduledForks. Forks which do not
+// change the execution rules (difficulty bomb delays aside) are omitted.
+var forkEIPs = map[string]struct{ enable, disable []int }{
+	"homesteadBlock":      {enable: []int{2, 7}},
+	"eip150Block":         {enable: []int{150}},
+	"eip155Block":         {enable: []int{155}},
+	"eip158Block":         {enable: []int{160, 161, 170}},
+	"byzantiumBlock":      {enable: []int{100, 140, 196, 197, 198, 211, 214, 649, 658}},
+	"constantinopleBlock": {enable: []int{145, 1014, 1052, 1234, 1283}},
+	"petersburgBlock":     {disable: []int{1283}},
+	"istanbulBlock":       {enable: []int{152, 1108, 1344, 1884, 2028, 2200}},
+	"muirGlacierBlock":    {enable: []int{2384}},
+	"berlinBlock":         {enable: []int{2565, 2718, 2929, 2930}},
+	"londonBlock":         {enable: []int{1559, 3198, 3529, 3541, 3554}},
+	"arrowGlacierBlock":   {enable: []int{4345}},
+	"grayGlacierBlock":    {enable: []int{5133}},
+	"eip2200Block":        {enable: []int{2200}},
+	"eip2929Block":        {enable: []int{2929}},
+	"shanghaiTime":        {enable: []int{3651, 3855, 3860, 4895, 6049}},
+	"cancunTime":          {enable: []int{1153, 4788, 4844, 5656, 6780, 7516}},
+	"pragueTime":          {enable: []int{2537, 2935, 6110, 7002, 7251, 7623, 7685, 7702}},
+	"verkleTime":          {enable: []int{4762, 6800}},
+}
+
+// EIPRange is the set of EIPs in effect from an activation point (a block
+// number or a timestamp) until the activation point of the next range.
+type EIPRange struct {
+	Block     *big.Int `json:"block,omitempty"`
+	Timestamp *uint64  `json:"timestamp,omitempty"`
+	EIPs      []int    `json:"eips"`
+}
+
+// ScheduledEIPs returns the EIP set in effect for each activation range of the
+// chain config, in activation order. EIPs scheduled ahead of their fork (e.g.
+// through EIP2200Block and EIP2929Block) are accounted for at their own
+// activation point.
+func (c *ChainConfig) ScheduledEIPs() []EIPRange {
+	forks := c.ScheduledForks()
+	sort.SliceStable(forks, func(i, j int) bool {
+		a, b := forks[i], forks[j]
+		switch {
+		case a.Block != nil && b.Block != nil:
+			return a.Block.Cmp(b.Block) < 0
+		case a.Block != nil || b.Block != nil:
+			return a.Block != nil // Block based forks precede timestamp based ones
+		default:
+			return *a.Timestamp < *b.Timestamp
+		}
+	})
+	var (
+		active = make(map[int]bool)
+		ranges []EIPRange
+	)
+	for _, fork := range forks {
+		change, ok := forkEIPs[fork.Name]
+		if !ok {
+			continue
+		}
+		for _, eip := range change.enable {
+			active[eip] = true
+		}
+		for _, eip := range change.disable {
+			delete(active, eip)
+		}
+		eips := make([]int, 0, len(active))
+		for eip := range active {
+			eips = append(eips, eip)
+		}
+		sort.Ints(eips)
+
+		// Forks sharing an activation point are collapsed into a single range
+		if n := len(ranges); n > 0 && configBlockEqual(ranges[n-1].Block, fork.Block) && configTimestampEqual(ranges[n-1].Timestamp, fork.Timestamp) {
+			ranges[n-1].EIPs = eips
+			continue
+		}
+		ranges = append(ranges, EIPRange{Block: fork.Block, Timestamp: fork.Timestamp, EIPs: eips})
+	}
+	return ranges
+}
+
 func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError {
 	if isForkBlockIncompatible(c.HomesteadBlock, newcfg.HomesteadBlock, headNumber) {
 		return newBlockCompatError("Homestead fork block", c.HomesteadBlock, newcfg.HomesteadBlock)
@@ -711,6 +907,15 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkBlockIncompatible(c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock, headNumber) {
 		return newBlockCompatError("Merge netsplit fork block", c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock)
 	}
+	if isForkBlockIncompatible(c.EIP2200Block, newcfg.EIP2200Block, headNumber) {
+		return newBlockCompatError("EIP2200 fork block", c.EIP2200Block, newcfg.EIP2200Block)
+	}
+	if isForkBlockIncompatible(c.EIP2929Block, newcfg.EIP2929Block, headNumber) {
+		return newBlockCompatError("EIP2929 fork block", c.EIP2929Block, newcfg.EIP2929Block)
+	}
+	if isForkBlockIncompatible(c.BMTVerifyBlock, newcfg.BMTVerifyBlock, headNumber) {
+		return newBlockCompatError("BMT verification fork block", c.BMTVerifyBlock, newcfg.BMTVerifyBlock)
+	}
 	if isForkTimestampIncompatible(c.ShanghaiTime, newcfg.ShanghaiTime, headTimestamp) {
 		return newTimestampCompatError("Shanghai fork timestamp", c.ShanghaiTime, newcfg.ShanghaiTime)
 	}
@@ -890,11 +1095,18 @@ func (err *ConfigCompatError) Error() string {
 type Rules struct {
 	ChainID                                                 *big.Int
 	IsHomestead, IsEIP150, IsEIP155, IsEIP158               bool
-	IsEIP2929, IsEIP4762                                    bool
+	IsEIP2200, IsEIP2929, IsEIP4762                         bool
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
 	IsBerlin, IsLondon                                      bool
 	IsMerge, IsShanghai, IsCancun, IsPrague                 bool
 	IsVerkle                                                bool
+	IsBMTVerify                                             bool
+
+	// BlockNumber and Timestamp identify the block the rules were derived for,
+	// allowing activation checks not covered by a fork flag (e.g. the ones of
+	// registered precompiles). They are unset in hand-crafted rules.
+	BlockNumber *big.Int
+	Timestamp   uint64
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -906,6 +1118,10 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 	// disallow setting Merge out of order
 	isMerge = isMerge && c.IsLondon(num)
 	isVerkle := isMerge && c.IsVerkle(num, timestamp)
+	var number *big.Int
+	if num != nil {
+		number = new(big.Int).Set(num)
+	}
 	return Rules{
 		ChainID:          new(big.Int).Set(chainID),
 		IsHomestead:      c.IsHomestead(num),
@@ -917,7 +1133,8 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsPetersburg:     c.IsPetersburg(num),
 		IsIstanbul:       c.IsIstanbul(num),
 		IsBerlin:         c.IsBerlin(num),
-		IsEIP2929:        c.IsBerlin(num) && !isVerkle,
+		IsEIP2200:        c.IsEIP2200(num),
+		IsEIP2929:        c.IsEIP2929(num) && !isVerkle,
 		IsLondon:         c.IsLondon(num),
 		IsMerge:          isMerge,
 		IsShanghai:       isMerge && c.IsShanghai(num, timestamp),
@@ -925,5 +1142,8 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsPrague:         isMerge && c.IsPrague(num, timestamp),
 		IsVerkle:         isVerkle,
 		IsEIP4762:        isVerkle,
+		IsBMTVerify:      c.IsBMTVerify(num),
+		BlockNumber:      number,
+		Timestamp:        timestamp,
 	}
 }
@@ -19,6 +19,7 @@ package params
 import (
 	"math/big"
 	"reflect"
+	"slices"
 	"testing"
 	"time"
 
@@ -155,3 +156,119 @@ func TestTimestampCompatError(t *testing.T) {
 	require.Equal(t, newTimestampCompatError(errWhat, newUint64(0), newUint64(1681338455)).Error(),
 		"mismatching Shanghai fork timestamp in database (have timestamp 0, want timestamp 1681338455, rewindto timestamp 0)")
 }
+
+func TestScheduledForks(t *testing.T) {
+	var (
+		shanghai = uint64(100)
+		config   = &ChainConfig{
+			HomesteadBlock: big.NewInt(0),
+			EIP150Block:    big.NewInt(10),
+			ShanghaiTime:   &shanghai,
+		}
+		forks = config.ScheduledForks()
+	)
+	if len(forks) != 3 {
+		t.Fatalf("fork count mismatch: have %d, want %d", len(forks), 3)
+	}
+	if forks[1].Name != "eip150Block" || forks[1].Block.Uint64() != 10 {
+		t.Errorf("eip150 fork mismatch: have %+v", forks[1])
+	}
+	if forks[2].Name != "shanghaiTime" || forks[2].Block != nil || *forks[2].Timestamp != shanghai {
+		t.Errorf("shanghai fork mismatch: have %+v", forks[2])
+	}
+}
+
+func TestScheduledEIPs(t *testing.T) {
+	var (
+		cancun = uint64(1000)
+		config = &ChainConfig{
+			HomesteadBlock:      big.NewInt(0),
+			EIP150Block:         big.NewInt(0),
+			EIP155Block:         big.NewInt(0),
+			EIP158Block:         big.NewInt(0),
+			ByzantiumBlock:      big.NewInt(0),
+			ConstantinopleBlock: big.NewInt(0),
+			PetersburgBlock:     big.NewInt(10),
+			IstanbulBlock:       big.NewInt(100),
+			BerlinBlock:         big.NewInt(200),
+			EIP2200Block:        big.NewInt(50),
+			EIP2929Block:        big.NewInt(50),
+			CancunTime:          &cancun,
+		}
+		ranges = config.ScheduledEIPs()
+	)
+	has := func(r EIPRange, eip int) bool {
+		return slices.Contains(r.EIPs, eip)
+	}
+	if len(ranges) != 6 {
+		t.Fatalf("range count mismatch: have %d, want %d: %+v", len(ranges), 6, ranges)
+	}
+	// Genesis: everything up to Constantinople, including the net gas metering
+	if r := ranges[0]; r.Block.Sign() != 0 || !has(r, 150) || !has(r, 1283) || has(r, 2200) {
+		t.Errorf("genesis range mismatch: %+v", r)
+	}
+	// Petersburg removes EIP-1283 again
+	if r := ranges[1]; r.Block.Uint64() != 10 || has(r, 1283) {
+		t.Errorf("petersburg range mismatch: %+v", r)
+	}
+	// The early activations share a single range ahead of their forks
+	if r := ranges[2]; r.Block.Uint64() != 50 || !has(r, 2200) || !has(r, 2929) || has(r, 1884) || has(r, 2930) {
+		t.Errorf("early activation range mismatch: %+v", r)
+	}
+	if r := ranges[3]; r.Block.Uint64() != 100 || !has(r, 1884) || has(r, 2930) {
+		t.Errorf("istanbul range mismatch: %+v", r)
+	}
+	if r := ranges[4]; r.Block.Uint64() != 200 || !has(r, 2930) || has(r, 4844) {
+		t.Errorf("berlin range mismatch: %+v", r)
+	}
+	if r := ranges[5]; r.Block != nil || *r.Timestamp != cancun || !has(r, 2930) || !has(r, 4844) {
+		t.Errorf("cancun range mismatch: %+v", r)
+	}
+}
+
+func TestCheckEIPOverrideOrder(t *testing.T) {
+	base := func() *ChainConfig {
+		return &ChainConfig{
+			HomesteadBlock:      big.NewInt(0),
+			EIP150Block:         big.NewInt(0),
+			EIP155Block:         big.NewInt(0),
+			EIP158Block:         big.NewInt(0),
+			ByzantiumBlock:      big.NewInt(0),
+			ConstantinopleBlock: big.NewInt(0),
+			PetersburgBlock:     big.NewInt(10),
+			IstanbulBlock:       big.NewInt(100),
+			BerlinBlock:         big.NewInt(200),
+		}
+	}
+	tests := []struct {
+		eip2200, eip2929 *big.Int
+		valid            bool
+	}{
+		{nil, nil, true},
+		{big.NewInt(50), nil, true},
+		{big.NewInt(50), big.NewInt(60), true},
+		{big.NewInt(50), big.NewInt(50), true},
+		{nil, big.NewInt(150), true},             // EIP-2200 active through Istanbul
+		{big.NewInt(5), nil, false},              // before Petersburg
+		{big.NewInt(100), nil, false},            // not before Istanbul
+		{nil, big.NewInt(50), false},             // before EIP-2200
+		{big.NewInt(60), big.NewInt(50), false},  // before EIP-2200
+		{big.NewInt(50), big.NewInt(200), false}, // not before Berlin
+	}
+	for i, tt := range tests {
+		config := base()
+		config.EIP2200Block, config.EIP2929Block = tt.eip2200, tt.eip2929
+		if err := config.CheckConfigForkOrder(); (err == nil) != tt.valid {
+			t.Errorf("test %d: validity mismatch: have %v, want valid %v", i, err, tt.valid)
+		}
+	}
+	// The overrides must be reflected in the rules
+	config := base()
+	config.EIP2200Block, config.EIP2929Block = big.NewInt(50), big.NewInt(60)
+	if r := config.Rules(big.NewInt(55), false, 0); !r.IsEIP2200 || r.IsEIP2929 || r.IsIstanbul {
+		t.Errorf("rules mismatch at block 55: %+v", r)
+	}
+	if r := config.Rules(big.NewInt(60), false, 0); !r.IsEIP2200 || !r.IsEIP2929 || r.IsBerlin {
+		t.Errorf("rules mismatch at block 60: %+v", r)
+	}
+}
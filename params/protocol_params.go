@@ -160,6 +160,10 @@ const (
 	Bls12381MapG1Gas          uint64 = 5500  // Gas price for BLS12-381 mapping field element to G1 operation
 	Bls12381MapG2Gas          uint64 = 75000 // Gas price for BLS12-381 mapping field element to G2 operation
 
+	BMTVerifyBaseGas     uint64 = 600 // Base price for a binary Merkle tree inclusion proof verification
+	BMTVerifyPerLevelGas uint64 = 60  // Per-level price for a binary Merkle tree inclusion proof verification
+	BMTVerifyMaxDepth           = 64  // Maximum depth of a binary Merkle tree inclusion proof
+
 	// The Refund Quotient is the cap on how much of the used gas can be refunded. Before EIP-3529,
 	// up to half the consumed gas could be refunded. Redefined as 1/5th in EIP-3529
 	RefundQuotient        uint64 = 2
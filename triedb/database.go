@@ -19,6 +19,7 @@ package triedb
 import (
 	"errors"
 
+	"github.com/VictoriaMetrics/fastcache"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -80,6 +81,10 @@ type backend interface {
 	// to disk. Report specifies whether logs will be displayed in info level.
 	Commit(root common.Hash, report bool) error
 
+	// CleanCacheStats returns the usage statistics of the clean node cache, or
+	// nil if the cache is disabled.
+	CleanCacheStats() *fastcache.Stats
+
 	// Close closes the trie database backend and releases all held resources.
 	Close() error
 
@@ -170,6 +175,12 @@ func (db *Database) Size() (common.StorageSize, common.StorageSize, common.Stora
 	return diffs, nodes, preimages
 }
 
+// CleanCacheStats returns the usage statistics of the clean trie node cache, or
+// nil if the cache is disabled.
+func (db *Database) CleanCacheStats() *fastcache.Stats {
+	return db.backend.CleanCacheStats()
+}
+
 // Initialized returns an indicator if the state data is already initialized
 // according to the state scheme.
 func (db *Database) Initialized(genesisRoot common.Hash) bool {
@@ -608,6 +608,17 @@ func (db *Database) Size() (common.StorageSize, common.StorageSize) {
 	return 0, db.dirtiesSize + db.childrenSize + metadataSize
 }
 
+// CleanCacheStats returns the usage statistics of the clean node cache, or nil
+// if the cache is disabled.
+func (db *Database) CleanCacheStats() *fastcache.Stats {
+	if db.cleans == nil {
+		return nil
+	}
+	var stats fastcache.Stats
+	db.cleans.UpdateStats(&stats)
+	return &stats
+}
+
 // Close closes the trie database and releases all held resources.
 func (db *Database) Close() error {
 	if db.cleans != nil {
@@ -23,6 +23,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/VictoriaMetrics/fastcache"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -463,6 +464,18 @@ func (db *Database) Size() (diffs common.StorageSize, nodes common.StorageSize)
 	return diffs, nodes
 }
 
+// CleanCacheStats returns the usage statistics of the clean node cache, or nil
+// if the cache is disabled.
+func (db *Database) CleanCacheStats() *fastcache.Stats {
+	dl := db.tree.bottom()
+	if dl == nil || dl.cleans == nil {
+		return nil
+	}
+	var stats fastcache.Stats
+	dl.cleans.UpdateStats(&stats)
+	return &stats
+}
+
 // Initialized returns an indicator if the state data is already
 // initialized in path-based scheme.
 func (db *Database) Initialized(genesisRoot common.Hash) bool {